@@ -0,0 +1,115 @@
+package taorm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timeNow returns the current time for automatic timestamps.
+var timeNow = time.Now
+
+var timeType = reflect.TypeOf(time.Time{})
+var timePtrType = reflect.TypeOf((*time.Time)(nil))
+
+// _AutoTime is a column that is set to the current time automatically.
+//
+// Columns tagged with `autoCreateTime` are set by Create, and columns tagged
+// with `autoUpdateTime` are set by Create, UpdateModel, UpdateMap, the
+// updates of Upsert, and soft deletes.
+// The column can be time.Time, *time.Time, or integers of unix seconds, or
+// unix milliseconds if tagged like `autoCreateTime:milli`.
+type _AutoTime struct {
+	field  _FieldInfo
+	update bool // tagged with autoUpdateTime
+	milli  bool // unix milliseconds for integer columns
+}
+
+// getAutoTime gets the automatic timestamp option of field.
+func getAutoTime(field reflect.StructField) (update bool, milli bool, ok bool) {
+	if unit, ok := getTagOption(field, "autoCreateTime"); ok {
+		return false, unit == "milli", true
+	}
+	if unit, ok := getTagOption(field, "autoUpdateTime"); ok {
+		return true, unit == "milli", true
+	}
+	return false, false, false
+}
+
+// addAutoTime records the automatic timestamp column of field.
+func (s *_StructInfo) addAutoTime(field reflect.StructField, fi _FieldInfo) error {
+	update, milli, ok := getAutoTime(field)
+	if !ok {
+		return nil
+	}
+	switch fi._type.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+	default:
+		if fi._type != timeType && fi._type != timePtrType {
+			return fmt.Errorf("taorm: auto time column %s must be time.Time, *time.Time, int, int64, uint or uint64", fi.name)
+		}
+	}
+	s.autoTimes = append(s.autoTimes, _AutoTime{
+		field:  fi,
+		update: update,
+		milli:  milli,
+	})
+	return nil
+}
+
+// value returns now in the type of the column.
+func (t _AutoTime) value(now time.Time) reflect.Value {
+	var v reflect.Value
+	switch {
+	case t.field._type == timeType:
+		v = reflect.ValueOf(now)
+	case t.field._type == timePtrType:
+		v = reflect.ValueOf(&now)
+	case t.milli:
+		v = reflect.ValueOf(now.UnixMilli())
+	default:
+		v = reflect.ValueOf(now.Unix())
+	}
+	return v.Convert(t.field._type)
+}
+
+// setCreateTimes sets all automatic timestamp columns of out that are zero.
+func (s *_StructInfo) setCreateTimes(out interface{}, now time.Time) {
+	for _, t := range s.autoTimes {
+		if v := s.valueOf(out, t.field); v.IsZero() {
+			v.Set(t.value(now))
+		}
+	}
+}
+
+// setUpdateTimes sets the autoUpdateTime columns of out.
+func (s *_StructInfo) setUpdateTimes(out interface{}, now time.Time) {
+	for _, t := range s.autoTimes {
+		if t.update {
+			s.valueOf(out, t.field).Set(t.value(now))
+		}
+	}
+}
+
+// withUpdateTimes returns a copy of fields with the autoUpdateTime columns
+// set, unless they are already in fields.
+func (s *_StructInfo) withUpdateTimes(fields M, now time.Time) M {
+	copied := false
+	for _, t := range s.autoTimes {
+		if !t.update {
+			continue
+		}
+		if _, ok := fields[t.field.name]; ok {
+			continue
+		}
+		if !copied {
+			m := make(M, len(fields)+1)
+			for k, v := range fields {
+				m[k] = v
+			}
+			fields, copied = m, true
+		}
+		fields[t.field.name] = t.value(now).Interface()
+	}
+	return fields
+}
@@ -0,0 +1,107 @@
+package taorm
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Article struct {
+	ID        int64
+	Title     string
+	CreatedAt time.Time `taorm:"autoCreateTime"`
+	UpdatedAt int64     `taorm:"autoUpdateTime:milli"`
+}
+
+func (Article) TableName() string {
+	return `articles`
+}
+
+func TestAutoTime(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	logger := &_TestLogger{}
+	tdb := NewDB(db).WithLogger(logger)
+
+	article := Article{ID: 1, Title: `a`}
+	assert.NoError(t, tdb.Model(&article).Create())
+	assert.Equal(t, now, article.CreatedAt)
+	assert.Equal(t, now.UnixMilli(), article.UpdatedAt)
+
+	created := time.Unix(1600000000, 0)
+	article = Article{ID: 2, Title: `b`, CreatedAt: created}
+	assert.NoError(t, tdb.Model(&article).Create())
+	assert.Equal(t, created, article.CreatedAt)
+
+	now = now.Add(time.Second)
+	_, err := tdb.Model(&article).UpdateModel(&article)
+	assert.NoError(t, err)
+	assert.Equal(t, created, article.CreatedAt)
+	assert.Equal(t, now.UnixMilli(), article.UpdatedAt)
+
+	updates := M{`title`: `c`}
+	_, err = tdb.From(Article{}).Where(`id=?`, 1).UpdateMap(updates)
+	assert.NoError(t, err)
+	assert.Equal(t, M{`title`: `c`}, updates)
+
+	last := logger.logs[len(logger.logs)-1]
+	assert.Equal(t, `UPDATE articles SET title=?,updated_at=? WHERE (id=?)`, last.Query)
+	assert.Equal(t, []interface{}{`c`, now.UnixMilli(), 1}, last.Args)
+
+	assert.Equal(t,
+		fmt.Sprintf(`UPDATE articles SET title='c',updated_at=%d WHERE (id=1)`, now.UnixMilli()),
+		tdb.Model(Article{ID: 1}).UpdateMapSQL(M{`title`: `c`}),
+	)
+
+	_, err = tdb.From(Article{}).Where(`id=?`, 1).UpdateMap(M{`updated_at`: 0})
+	assert.NoError(t, err)
+	last = logger.logs[len(logger.logs)-1]
+	assert.Equal(t, []interface{}{0, 1}, last.Args)
+
+	type BadTime struct {
+		CreatedAt string `taorm:"autoCreateTime"`
+	}
+	_, err = getRegistered(BadTime{})
+	assert.Error(t, err)
+}
+
+type Note struct {
+	ID        int64
+	Content   string
+	UpdatedAt *time.Time   `taorm:"autoUpdateTime"`
+	DeletedAt sql.NullTime `taorm:"softDelete"`
+}
+
+func (Note) TableName() string {
+	return `notes`
+}
+
+func TestAutoTimeOfOtherUpdates(t *testing.T) {
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tdb := NewDB(newTestDB(t))
+
+	note := Note{Content: `a`}
+	assert.NoError(t, tdb.Model(&note).Create())
+	assert.Equal(t, now, *note.UpdatedAt)
+
+	assertSQLs(t, []_SQLTest{
+		{
+			`UPDATE notes SET deleted_at=2023-11-14 22:13:20 +0000 UTC,updated_at=2023-11-14 22:13:20 +0000 UTC WHERE (id=1) AND (deleted_at IS NULL)`,
+			tdb.Model(&Note{ID: 1}).DeleteSQL(),
+		},
+		{
+			`INSERT INTO notes (id,content,updated_at,deleted_at) VALUES (1,'a',NULL,NULL) ON DUPLICATE KEY UPDATE content='b',updated_at=2023-11-14 22:13:20 +0000 UTC`,
+			tdb.Model(&Note{ID: 1, Content: `a`}).UpsertSQL(nil, M{`content`: `b`}),
+		},
+	})
+}
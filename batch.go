@@ -0,0 +1,199 @@
+package taorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isSliceModel returns true if model is a slice or a pointer to slice.
+func isSliceModel(model interface{}) bool {
+	ty := reflect.TypeOf(model)
+	for ty != nil && ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	return ty != nil && ty.Kind() == reflect.Slice
+}
+
+// sliceElems returns pointers to elements of the slice model.
+//
+// model can be []Struct, []*Struct, *[]Struct or *[]*Struct.
+// Elements of []Struct are addressable, so they can be written back too.
+func sliceElems(model interface{}) []interface{} {
+	value := reflect.ValueOf(model)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	elems := make([]interface{}, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		elem := value.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elems = append(elems, elem.Interface())
+		} else {
+			elems = append(elems, elem.Addr().Interface())
+		}
+	}
+	return elems
+}
+
+// CreateBatch inserts all elements of the slice model by multi-row INSERTs.
+//
+// Each INSERT has at most batchSize rows, or as many rows as the dialect
+// allows if batchSize <= 0.
+// Auto-generated primary keys and columns set by Returning are read back
+// into the elements only if the dialect supports RETURNING.
+//
+// Batches are not inserted atomically unless in a transaction.
+func (s *Stmt) CreateBatch(batchSize int) error {
+	return s.CreateBatchContext(context.Background(), batchSize)
+}
+
+// CreateBatchContext ...
+func (s *Stmt) CreateBatchContext(ctx context.Context, batchSize int) error {
+	panicIf(!isSliceModel(s.model), "model is not a slice")
+	return WrapError(s.createBatch(ctx, batchSize))
+}
+
+// MustCreateBatch ...
+func (s *Stmt) MustCreateBatch(batchSize int) {
+	if err := s.CreateBatch(batchSize); err != nil {
+		panic(err)
+	}
+}
+
+func (s *Stmt) createBatch(ctx context.Context, batchSize int) error {
+	elems := sliceElems(s.model)
+	if len(elems) == 0 {
+		return nil
+	}
+
+	if err := s.info.callHooks(_BeforeCreate, s.db, elems); err != nil {
+		return err
+	}
+	now := timeNow()
+	for _, elem := range elems {
+		s.info.setCreateTimes(elem, now)
+	}
+
+	withID, err := s.batchWithID(s.info, elems)
+	if err != nil {
+		return err
+	}
+
+	columns := len(s.info.insertFields)
+	if withID {
+		columns += len(s.info.pkeyFields)
+	}
+	if columns == 0 {
+		return ErrNoFields
+	}
+
+	// bind variables used by the conflict clause of each statement.
+	reserved := 0
+	if s.conflict != nil {
+		_, args := buildSets(s.db.dialect, s.conflict.updates)
+		reserved = len(args)
+	}
+
+	size := (s.db.dialect.MaxPlaceholders() - reserved) / columns
+	if size <= 0 {
+		return fmt.Errorf("taorm: %d bind variables of a row exceed the limit %d of the dialect",
+			columns+reserved, s.db.dialect.MaxPlaceholders())
+	}
+	if batchSize > 0 && batchSize < size {
+		size = batchSize
+	}
+
+	for start := 0; start < len(elems); start += size {
+		end := start + size
+		if end > len(elems) {
+			end = len(elems)
+		}
+		chunk := elems[start:end]
+
+		query, args, returning, err := s.buildCreateBatch(s.info, chunk)
+		if err != nil {
+			return err
+		}
+
+		if !returning {
+			if _, err := s.db.exec(ctx, OpCreate, s.table(), query, args...); err != nil {
+				return err
+			}
+		} else if err := s.scanReturning(ctx, s.info, chunk, query, args); err != nil {
+			return err
+		}
+	}
+
+	return s.info.callHooks(_AfterCreate, s.db, elems)
+}
+
+// batchWithID reports whether primary keys are inserted.
+//
+// Primary keys must be either all set or all not set.
+func (s *Stmt) batchWithID(info *_StructInfo, elems []interface{}) (bool, error) {
+	if len(info.pkeyFields) > 1 {
+		return true, nil
+	}
+	set := 0
+	for _, elem := range elems {
+		if _, ok := info.getPrimaryKey(elem); ok {
+			set++
+		}
+	}
+	if set > 0 && set < len(elems) {
+		return false, fmt.Errorf("primary keys are set for some of the elements only")
+	}
+	return set > 0, nil
+}
+
+// buildCreateBatch builds the multi-row INSERT for elems.
+// returning is true if the query returns rows to be scanned into elems.
+func (s *Stmt) buildCreateBatch(info *_StructInfo, elems []interface{}) (query string, args []interface{}, returning bool, err error) {
+	panicIf(len(s.tableNames) != 1, "model length is not 1")
+	panicIf(s.raw.query != "", "cannot use raw here")
+
+	if len(elems) == 0 {
+		return "", nil, false, ErrNoFields
+	}
+
+	withID, err := s.batchWithID(info, elems)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	for _, elem := range elems {
+		if withID {
+			pks, _ := info.getPrimaryKey(elem)
+			args = append(args, pks...)
+		}
+		args = append(args, info.ifacesOf(elem)...)
+	}
+	if len(args) == 0 {
+		return "", nil, false, ErrNoFields
+	}
+
+	query = info.insertStrOf(s.db.dialect, withID)
+	marks := query[strings.LastIndex(query, "("):]
+	query += strings.Repeat(","+marks, len(elems)-1)
+
+	query, conflictArgs, err := s.buildConflict(info, query)
+	if err != nil {
+		return "", nil, false, err
+	}
+	args = append(args, conflictArgs...)
+
+	// ignored rows are not returned, so returned keys cannot be matched.
+	ignore := s.conflict != nil && s.conflict.ignore
+	columns := s.returning
+	if len(columns) == 0 && !withID && !ignore && info.hasSinglePrimaryKey() && s.db.dialect.Returning() {
+		columns = info.pkeyNames()
+	}
+	clause, err := s.buildReturning(columns)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return query + clause, args, clause != "", nil
+}
@@ -0,0 +1,88 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBatchSQL(t *testing.T) {
+	db := newTestDB(t)
+
+	my := NewDB(db)
+	pg := NewDBWithDialect(db, PostgreSQL)
+
+	users := []User{{Name: `a`, Age: 1}, {Name: `b`, Age: 2}}
+
+	assert.Equal(t,
+		`INSERT INTO users (name,age) VALUES ('a',1),('b',2)`,
+		my.Model(users).CreateSQL(),
+	)
+	assert.Equal(t,
+		`INSERT INTO users (name,age) VALUES ('a',1),('b',2) RETURNING id`,
+		pg.Model(&users).CreateSQL(),
+	)
+	assert.Equal(t,
+		`INSERT INTO users (id,name,age) VALUES (1,'a',1),(2,'b',2)`,
+		my.Model([]*User{{ID: 1, Name: `a`, Age: 1}, {ID: 2, Name: `b`, Age: 2}}).CreateSQL(),
+	)
+	assert.Equal(t,
+		`INSERT INTO likes (user_id,like_id) VALUES (1,2),(1,3)`,
+		my.Model([]Like{{UserID: 1, LikeID: 2}, {UserID: 1, LikeID: 3}}).CreateSQL(),
+	)
+	assert.Panics(t, func() {
+		my.Model([]User{{ID: 1}, {}}).CreateSQL()
+	})
+	assert.Panics(t, func() {
+		my.Model(users).DeleteSQL()
+	})
+	assert.Panics(t, func() {
+		my.Model(&users).UpdateMapSQL(M{`age`: 1})
+	})
+	assert.Panics(t, func() {
+		my.Model(&users).UpdateModelSQL(User{Age: 1})
+	})
+}
+
+func TestCreateBatch(t *testing.T) {
+	db := newTestDB(t)
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	users := make([]User, 5)
+	assert.NoError(t, NewDB(db).Model(&users).CreateBatch(2))
+	assert.Equal(t, []string{
+		`INSERT INTO users (name,age) VALUES (?,?),(?,?)`,
+		`INSERT INTO users (name,age) VALUES (?,?),(?,?)`,
+		`INSERT INTO users (name,age) VALUES (?,?)`,
+	}, *queries)
+
+	*queries = nil
+	mimic.SetRows([]string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}})
+	pusers := []*User{{Name: `a`}, {Name: `b`}, {Name: `c`}}
+	assert.NoError(t, NewDBWithDialect(db, PostgreSQL).Model(pusers).Create())
+	assert.Equal(t, []string{
+		`INSERT INTO users (name,age) VALUES ($1,$2),($3,$4),($5,$6) RETURNING id`,
+	}, *queries)
+	for i, u := range pusers {
+		assert.Equal(t, int64(i+1), u.ID)
+	}
+
+	mimic.SetRows([]string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}})
+	pusers = []*User{{Name: `a`}, {Name: `b`}, {Name: `c`}}
+	err := NewDBWithDialect(db, PostgreSQL).Model(pusers).Create()
+	assert.True(t, IsNotFoundError(err))
+
+	*queries = nil
+	small := NewDBWithDialect(db, _SmallDialect{MySQL})
+	assert.NoError(t, small.Model([]Like{{UserID: 1, LikeID: 2}, {UserID: 1, LikeID: 3}}).Create())
+	assert.Equal(t, []string{
+		`INSERT INTO likes (user_id,like_id) VALUES (?,?)`,
+		`INSERT INTO likes (user_id,like_id) VALUES (?,?)`,
+	}, *queries)
+	assert.Error(t, small.Model([]User{{ID: 1, Name: `a`}}).Create())
+	assert.Error(t, small.Model([]Like{{UserID: 1, LikeID: 2}}).Upsert(nil, M{`like_id`: 3}))
+}
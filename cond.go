@@ -0,0 +1,88 @@
+package taorm
+
+import (
+	"strings"
+)
+
+// _Cond is a condition of WHERE, which can be combined by And, Or and Not.
+type _Cond struct {
+	where _Where  // the condition if this is not a group
+	op    string  // "AND" or "OR" if this is a group
+	conds []_Cond // conditions in the group
+	not   bool    // negated
+}
+
+// Cond creates a condition.
+//
+// It is like Stmt.Where, slices in args are expanded.
+func Cond(query string, args ...interface{}) _Cond {
+	return _Cond{
+		where: _Where{
+			query: query,
+			args:  args,
+		},
+	}
+}
+
+// And creates a condition that all conds are true.
+func And(conds ..._Cond) _Cond {
+	return _Cond{
+		op:    "AND",
+		conds: conds,
+	}
+}
+
+// Or creates a condition that any of conds is true.
+func Or(conds ..._Cond) _Cond {
+	return _Cond{
+		op:    "OR",
+		conds: conds,
+	}
+}
+
+// Not negates cond.
+func Not(cond _Cond) _Cond {
+	cond.not = !cond.not
+	return cond
+}
+
+// empty returns true if the condition is an empty group.
+func (c _Cond) empty() bool {
+	if c.op == "" {
+		return false
+	}
+	for _, x := range c.conds {
+		if !x.empty() {
+			return false
+		}
+	}
+	return true
+}
+
+func (c _Cond) build() (query string, args []interface{}) {
+	if c.op == "" {
+		query, args = c.where.build()
+	} else {
+		conds := make([]_Cond, 0, len(c.conds))
+		for _, x := range c.conds {
+			if !x.empty() {
+				conds = append(conds, x)
+			}
+		}
+		if len(conds) == 1 {
+			query, args = conds[0].build()
+		} else {
+			queries := make([]string, 0, len(conds))
+			for _, x := range conds {
+				q, a := x.build()
+				queries = append(queries, "("+q+")")
+				args = append(args, a...)
+			}
+			query = strings.Join(queries, " "+c.op+" ")
+		}
+	}
+	if c.not {
+		query = "NOT (" + query + ")"
+	}
+	return
+}
@@ -1,26 +1,50 @@
 package taorm
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
 )
 
 // DB wraps sql.DB.
 type DB struct {
 	rdb *sql.DB // raw db
 	_SQLCommon
-	isTx bool
+	dialect  Dialect
+	logger   Logger
+	scanMode ScanMode
+	isTx     bool
+	depth    int // savepoint depth in a tx
+
+	middlewares []Middleware
 }
 
 // NewDB news a taorm DB from raw sql.DB.
+//
+// The database is assumed to be MySQL.
 func NewDB(db *sql.DB) *DB {
+	return NewDBWithDialect(db, MySQL)
+}
+
+// NewDBWithDialect news a taorm DB from raw sql.DB that speaks dialect.
+func NewDBWithDialect(db *sql.DB, dialect Dialect) *DB {
+	registerDialect(dialect)
 	t := &DB{
 		rdb:        db,
 		_SQLCommon: db,
+		dialect:    dialect,
 		isTx:       false,
 	}
 	return t
 }
 
+// Dialect returns the dialect of the db.
+func (db *DB) Dialect() Dialect {
+	return db.dialect
+}
+
 // If the db is currently in a Tx, true will be returned.
 // This is allowing for doing things in a single transaction before opening a new Tx.
 func (db *DB) IsTx() bool {
@@ -31,18 +55,178 @@ func (db *DB) IsTx() bool {
 //
 // If the callback returns an error, the transaction is rolled back.
 // if the callback panics, the transaction is rolled back and what's recovered is paniced again.
+//
+// If db is already in a transaction, a savepoint is created instead, which is
+// rolled back to or released the same way as a transaction.
 func (db *DB) TxCall(callback func(tx *DB) error) error {
-	rtx, err := db.rdb.Begin()
-	if err != nil {
+	return db.TxCallContext(context.Background(), callback)
+}
+
+// TxCallContext is like TxCall but the transaction is bound to ctx.
+//
+// If ctx is done before the transaction commits, the transaction is rolled back.
+func (db *DB) TxCallContext(ctx context.Context, callback func(tx *DB) error) error {
+	return db.TxCallContextWithOptions(ctx, nil, callback)
+}
+
+// TxOptions are options for a transaction.
+type TxOptions struct {
+	// Isolation is the isolation level of the transaction.
+	// The default level of the database is used if zero.
+	Isolation sql.IsolationLevel
+
+	// ReadOnly makes the transaction read-only.
+	ReadOnly bool
+
+	// Retry re-runs the whole transaction if it fails because of
+	// a deadlock or a serialization failure.
+	// The transaction is not retried if nil.
+	Retry *RetryPolicy
+}
+
+// RetryPolicy is the retry policy for a transaction.
+//
+// The callback of a retried transaction is called multiple times,
+// so it must not have side effects other than the database.
+type RetryPolicy struct {
+	// MaxAttempts is the max times the transaction is run, including the first run.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry.
+	// It is doubled before each following retry.
+	Backoff time.Duration
+
+	// MaxBackoff is the max delay between retries.
+	// The delay is not limited if zero.
+	MaxBackoff time.Duration
+}
+
+// TxCallWithOptions is like TxCall but the transaction is started with opts.
+//
+// opts is ignored if db is already in a transaction, in which case
+// isolation level cannot be changed and retrying is left to the outermost
+// transaction.
+func (db *DB) TxCallWithOptions(opts *TxOptions, callback func(tx *DB) error) error {
+	return db.TxCallContextWithOptions(context.Background(), opts, callback)
+}
+
+// TxCallContextWithOptions is like TxCallWithOptions but the transaction is bound to ctx.
+func (db *DB) TxCallContextWithOptions(ctx context.Context, opts *TxOptions, callback func(tx *DB) error) error {
+	if db.isTx {
+		return db.savepointCall(ctx, callback)
+	}
+
+	var txOpts *sql.TxOptions
+	var retry RetryPolicy
+	if opts != nil {
+		txOpts = &sql.TxOptions{
+			Isolation: opts.Isolation,
+			ReadOnly:  opts.ReadOnly,
+		}
+		if opts.Retry != nil {
+			retry = *opts.Retry
+		}
+	}
+
+	backoff := retry.Backoff
+	for attempt := 1; ; attempt++ {
+		err := db.txCall(ctx, txOpts, callback)
+		if err == nil || attempt >= retry.MaxAttempts || !IsDeadlockError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if retry.MaxBackoff > 0 && backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+// txCall runs callback in a new transaction.
+func (db *DB) txCall(ctx context.Context, opts *sql.TxOptions, callback func(tx *DB) error) error {
+	var rtx *sql.Tx
+	if err := db.execTx(ctx, `BEGIN`, func() (err error) {
+		rtx, err = db.rdb.BeginTx(ctx, opts)
+		return err
+	}); err != nil {
 		return WrapError(err)
 	}
 
-	tx := &DB{
-		rdb:        db.rdb,
-		_SQLCommon: rtx,
-		isTx:       true,
+	tx := *db
+	tx._SQLCommon = rtx
+	tx.isTx = true
+
+	return txCall(&tx, &_Tx{ctx: ctx, db: db, tx: rtx}, callback)
+}
+
+// savepointCall calls callback within a savepoint of the current transaction.
+func (db *DB) savepointCall(ctx context.Context, callback func(tx *DB) error) error {
+	tx := *db
+	tx.depth++
+
+	sp := &_Savepoint{
+		ctx:  ctx,
+		db:   &tx,
+		name: fmt.Sprintf(`taorm_sp_%d`, tx.depth),
+	}
+
+	if _, err := tx.exec(ctx, OpTx, "", `SAVEPOINT `+sp.name); err != nil {
+		return WrapError(err)
 	}
 
+	return txCall(&tx, sp, callback)
+}
+
+// _TxControl finishes a transaction or a savepoint.
+type _TxControl interface {
+	Commit() error
+	Rollback() error
+}
+
+// _Tx implements _TxControl for a transaction.
+// Commits and rollbacks go through middlewares of db.
+type _Tx struct {
+	ctx context.Context
+	db  *DB
+	tx  *sql.Tx
+}
+
+func (t *_Tx) Commit() error {
+	return t.db.execTx(t.ctx, `COMMIT`, t.tx.Commit)
+}
+
+func (t *_Tx) Rollback() error {
+	return t.db.execTx(t.ctx, `ROLLBACK`, t.tx.Rollback)
+}
+
+// _Savepoint implements _TxControl for a savepoint.
+type _Savepoint struct {
+	ctx  context.Context
+	db   *DB
+	name string
+}
+
+func (sp *_Savepoint) Commit() error {
+	_, err := sp.db.exec(sp.ctx, OpTx, "", `RELEASE SAVEPOINT `+sp.name)
+	return err
+}
+
+func (sp *_Savepoint) Rollback() error {
+	_, err := sp.db.exec(sp.ctx, OpTx, "", `ROLLBACK TO SAVEPOINT `+sp.name)
+	return err
+}
+
+// txCall calls callback with tx, and then commits or rolls back by ctl.
+//
+// Errors of rolling back are joined into the returned error, except for
+// sql.ErrTxDone if the transaction has been rolled back, e.g. by ctx.
+func txCall(tx *DB, ctl _TxControl, callback func(tx *DB) error) error {
 	var exception struct {
 		caught bool        // user callback threw an exception
 		what   interface{} // user thrown exception
@@ -60,18 +244,21 @@ func (db *DB) TxCall(callback func(tx *DB) error) error {
 	}
 
 	if err := catchCall(); err != nil {
-		// TODO: how to handle rollback errors?
-		rtx.Rollback()
+		if rbErr := ctl.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+			return errors.Join(err, WrapError(rbErr))
+		}
 		return err // user error, not wrapped
 	}
 
 	if exception.caught {
-		rtx.Rollback()
+		ctl.Rollback()
 		panic(exception.what) // user exception, not wrapped
 	}
 
-	if err = rtx.Commit(); err != nil {
-		rtx.Rollback()
+	if err := ctl.Commit(); err != nil {
+		if rbErr := ctl.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+			return errors.Join(WrapError(err), WrapError(rbErr))
+		}
 		return WrapError(err)
 	}
 
@@ -103,7 +290,7 @@ func (db *DB) Model(model interface{}) *Stmt {
 		panic(WrapError(err))
 	}
 
-	stmt.tableNames = append(stmt.tableNames, info.tableName)
+	stmt.tableNames = append(stmt.tableNames, quoteIdent(db.dialect, info.tableName))
 
 	stmt.info = info
 
@@ -136,6 +323,34 @@ func (db *DB) Raw(query string, args ...interface{}) Finder {
 	return stmt
 }
 
+// Exec executes a query without returning any rows.
+//
+// `?`s in query are rewritten to bind variables of the dialect,
+// and `??` to a literal `?`,
+// e.g. for the jsonb operators of PostgreSQL.
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext ...
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.exec(ctx, OpRaw, "", query, args...)
+}
+
+// Query executes a query that returns rows.
+//
+// `?`s in query are rewritten to bind variables of the dialect,
+// and `??` to a literal `?`,
+// e.g. for the jsonb operators of PostgreSQL.
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext ...
+func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.query(ctx, OpRaw, "", query, args...)
+}
+
 // --- stmt impl. ---
 //
 // Below are some commonly used functions to begin a preparing.
@@ -168,6 +383,11 @@ func (db *DB) Where(query string, args ...interface{}) *Stmt {
 	return db._New().Where(query, args...)
 }
 
+// WhereCond ...
+func (db *DB) WhereCond(c _Cond) *Stmt {
+	return db._New().WhereCond(c)
+}
+
 // WhereIf ...
 func (db *DB) WhereIf(cond bool, query string, args ...interface{}) *Stmt {
 	return db._New().WhereIf(cond, query, args...)
@@ -178,6 +398,11 @@ func (db *DB) Find(out interface{}) error {
 	return db._New().Find(out)
 }
 
+// FindContext ...
+func (db *DB) FindContext(ctx context.Context, out interface{}) error {
+	return db._New().FindContext(ctx, out)
+}
+
 // MustFind ...
 func (db *DB) MustFind(out interface{}) {
 	db._New().MustFind(out)
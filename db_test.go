@@ -0,0 +1,168 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordQueries records queries executed by the mimic driver until
+// the returned function is called.
+func recordQueries() (*[]string, func()) {
+	queries := []string{}
+	mimic.SetRecorder(func(query string) {
+		queries = append(queries, query)
+	})
+	return &queries, func() { mimic.SetRecorder(nil) }
+}
+
+// newTestDB opens a database of the mimic driver, which is closed with
+// rows of the driver reset when the test finishes.
+func newTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("mimic", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mimic.SetRows(nil, nil)
+		mimic.SetRowsFunc(nil)
+		mimic.SetRowsAffected(0)
+		db.Close()
+	})
+	return db
+}
+
+// _SQLTest is a test case of generated SQL.
+type _SQLTest struct {
+	want string
+	got  string
+}
+
+// assertSQLs asserts that all generated SQLs are wanted.
+func assertSQLs(t *testing.T, tests []_SQLTest) {
+	t.Helper()
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.got)
+	}
+}
+
+func TestTxCallSavepoint(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	errUser := errors.New("user error")
+
+	err := tdb.TxCall(func(tx *DB) error {
+		tx.MustExec(`1`)
+		assert.NoError(t, tx.TxCall(func(tx *DB) error {
+			tx.MustExec(`2`)
+			return tx.TxCall(func(tx *DB) error {
+				tx.MustExec(`3`)
+				return nil
+			})
+		}))
+		assert.Equal(t, errUser, tx.TxCall(func(tx *DB) error {
+			tx.MustExec(`4`)
+			return errUser
+		}))
+		assert.Panics(t, func() {
+			tx.TxCall(func(tx *DB) error {
+				panic(errUser)
+			})
+		})
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`BEGIN`,
+		`1`,
+		`SAVEPOINT taorm_sp_1`,
+		`2`,
+		`SAVEPOINT taorm_sp_2`,
+		`3`,
+		`RELEASE SAVEPOINT taorm_sp_2`,
+		`RELEASE SAVEPOINT taorm_sp_1`,
+		`SAVEPOINT taorm_sp_1`,
+		`4`,
+		`ROLLBACK TO SAVEPOINT taorm_sp_1`,
+		`SAVEPOINT taorm_sp_1`,
+		`ROLLBACK TO SAVEPOINT taorm_sp_1`,
+		`COMMIT`,
+	}, *queries)
+}
+
+func TestTxCallRetry(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	opts := &TxOptions{
+		Isolation: sql.LevelSerializable,
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+		},
+	}
+
+	deadlock := &mysql.MySQLError{Number: 1213, Message: `Deadlock found when trying to get lock`}
+
+	attempts := 0
+	err := tdb.TxCallWithOptions(opts, func(tx *DB) error {
+		attempts++
+		if attempts < 3 {
+			return WrapError(deadlock)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = tdb.TxCallWithOptions(opts, func(tx *DB) error {
+		attempts++
+		return deadlock
+	})
+	assert.True(t, IsDeadlockError(err))
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = tdb.TxCallWithOptions(opts, func(tx *DB) error {
+		attempts++
+		if attempts < 2 {
+			return fmt.Errorf("transfer: %w", deadlock)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	errUser := errors.New("user error")
+	err = tdb.TxCallWithOptions(opts, func(tx *DB) error {
+		attempts++
+		return errUser
+	})
+	assert.Equal(t, errUser, err)
+	assert.Equal(t, 1, attempts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = tdb.TxCallContext(ctx, func(tx *DB) error {
+		cancel()
+		time.Sleep(10 * time.Millisecond)
+		return errUser
+	})
+	assert.Equal(t, errUser, err)
+}
@@ -0,0 +1,383 @@
+package taorm
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Dialect abstracts the SQL differences between database servers.
+//
+// Statements are always built with `?` as bind variables, they are
+// rewritten by Placeholder right before being sent to the database.
+type Dialect interface {
+	// Name returns the name of the dialect, e.g. "mysql".
+	Name() string
+
+	// Placeholder returns the n-th (starting from 1) bind variable.
+	Placeholder(n int) string
+
+	// Quote quotes an identifier, e.g. a table name or a column name.
+	Quote(name string) string
+
+	// Limit returns the LIMIT/OFFSET clause with a leading space.
+	// limit or offset is not set if it is negative.
+	Limit(limit, offset int64) string
+
+	// LastInsertID reports whether the auto-generated primary key of an
+	// INSERT is read by sql.Result.LastInsertId.
+	// If false, it is read by appending a RETURNING clause.
+	LastInsertID() bool
+
+	// Returning reports whether INSERT ... RETURNING is supported.
+	Returning() bool
+
+	// MaxPlaceholders returns the max number of bind variables in a statement.
+	MaxPlaceholders() int
+
+	// Upsert returns the clause appended to an INSERT to update the existing
+	// row by sets if the row to insert conflicts on conflictColumns.
+	// sets is like `a=?,b=b+1`.
+	Upsert(conflictColumns []string, sets string) string
+
+	// InsertIgnore rewrites an INSERT statement to ignore conflicting rows.
+	InsertIgnore(query string) string
+
+	// TranslateError translates a driver error into a taorm *Error.
+	// nil is returned if the error is not recognised.
+	TranslateError(err error) error
+}
+
+// Built-in dialects.
+var (
+	MySQL      Dialect = _MySQL{}
+	PostgreSQL Dialect = _PostgreSQL{}
+	SQLite     Dialect = _SQLite{}
+)
+
+// dialects are used by WrapError to translate driver errors.
+var dialects = []Dialect{MySQL, PostgreSQL, SQLite}
+var dialectsLock = &sync.RWMutex{}
+
+// registerDialect makes the errors of a custom dialect known to WrapError.
+func registerDialect(d Dialect) {
+	dialectsLock.Lock()
+	defer dialectsLock.Unlock()
+	for _, x := range dialects {
+		if x.Name() == d.Name() {
+			return
+		}
+	}
+	dialects = append(dialects, d)
+}
+
+func translateError(err error) error {
+	dialectsLock.RLock()
+	defer dialectsLock.RUnlock()
+	for _, d := range dialects {
+		if e := d.TranslateError(err); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// nextMark returns the index of the first `?` in query, which is not in
+// quoted strings or identifiers, or -1 if there is none.
+// escaped reports whether it is `??`, an escaped `?` that is not a bind variable.
+//
+// Quotes in strings are escaped by backslashes or by doubling them.
+func nextMark(query string) (index int, escaped bool) {
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			return i, i+1 < len(query) && query[i+1] == '?'
+		}
+	}
+	return -1, false
+}
+
+// rebind rewrites `?`s in query to bind variables of the dialect.
+// `?`s in quoted strings or identifiers are left untouched.
+//
+// `??` is an escaped `?` that is not a bind variable, which is rewritten
+// to a single `?`, e.g.: `data ?? 'key'` for the jsonb operator `?` of
+// PostgreSQL.
+func rebind(d Dialect, query string) string {
+	if strings.IndexByte(query, '?') == -1 {
+		return query
+	}
+
+	sb := strings.Builder{}
+	sb.Grow(len(query) + 16)
+	n := 0
+	for {
+		i, escaped := nextMark(query)
+		if i == -1 {
+			break
+		}
+		sb.WriteString(query[:i])
+		if escaped {
+			sb.WriteByte('?')
+			query = query[i+2:]
+			continue
+		}
+		n++
+		sb.WriteString(d.Placeholder(n))
+		query = query[i+1:]
+	}
+	sb.WriteString(query)
+	return sb.String()
+}
+
+var reSimpleIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedWords are common SQL keywords that are likely to be used as names.
+var reservedWords = map[string]bool{
+	"all": true, "and": true, "as": true, "asc": true, "by": true,
+	"case": true, "check": true, "column": true, "default": true, "desc": true,
+	"from": true, "group": true, "in": true, "index": true, "is": true,
+	"join": true, "key": true, "like": true, "limit": true, "not": true,
+	"null": true, "offset": true, "on": true, "or": true, "order": true,
+	"primary": true, "references": true, "select": true, "table": true, "to": true,
+	"user": true, "values": true, "when": true, "where": true,
+}
+
+// quoteIdent quotes name by d only if name is not a simple identifier.
+func quoteIdent(d Dialect, name string) string {
+	if name == "" || !needsQuote(name) {
+		return name
+	}
+	return d.Quote(name)
+}
+
+func needsQuote(name string) bool {
+	return !reSimpleIdent.MatchString(name) || reservedWords[strings.ToLower(name)]
+}
+
+// onConflict builds the standard `ON CONFLICT ... DO UPDATE` clause.
+func onConflict(d Dialect, conflictColumns []string, sets string) string {
+	columns := make([]string, 0, len(conflictColumns))
+	for _, c := range conflictColumns {
+		columns = append(columns, quoteIdent(d, c))
+	}
+	return ` ON CONFLICT (` + strings.Join(columns, ",") + `) DO UPDATE SET ` + sets
+}
+
+type _MySQL struct{}
+
+func (_MySQL) Name() string {
+	return "mysql"
+}
+
+func (_MySQL) Placeholder(n int) string {
+	return "?"
+}
+
+func (_MySQL) Quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (_MySQL) Limit(limit, offset int64) (s string) {
+	if limit > 0 {
+		s += ` LIMIT ` + fmt.Sprint(limit)
+		if offset >= 0 {
+			s += ` OFFSET ` + fmt.Sprint(offset)
+		}
+	}
+	return
+}
+
+func (_MySQL) LastInsertID() bool {
+	return true
+}
+
+func (_MySQL) Returning() bool {
+	return false
+}
+
+func (_MySQL) MaxPlaceholders() int {
+	return 65535
+}
+
+// Upsert conflicts on any primary or unique key, conflictColumns are ignored.
+func (_MySQL) Upsert(conflictColumns []string, sets string) string {
+	return ` ON DUPLICATE KEY UPDATE ` + sets
+}
+
+func (_MySQL) InsertIgnore(query string) string {
+	return strings.Replace(query, `INSERT INTO `, `INSERT IGNORE INTO `, 1)
+}
+
+var reErr1062 = regexp.MustCompile(`Duplicate entry '([^']*)' for key '([^']+)'`)
+
+func (_MySQL) TranslateError(err error) error {
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return nil
+	}
+	switch myErr.Number {
+	case 1062:
+		matches := reErr1062.FindStringSubmatch(myErr.Message)
+		if matches == nil {
+			break
+		}
+		return &Error{
+			Err: &DupKeyError{
+				Key:   matches[2],
+				Value: matches[1],
+			},
+			Raw: myErr,
+		}
+	case 1205, 1213:
+		// lock wait timeout, deadlock
+		return &Error{Err: &DeadlockError{}, Raw: myErr}
+	}
+	return nil
+}
+
+type _PostgreSQL struct{}
+
+func (_PostgreSQL) Name() string {
+	return "postgres"
+}
+
+func (_PostgreSQL) Placeholder(n int) string {
+	return "$" + fmt.Sprint(n)
+}
+
+func (_PostgreSQL) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (_PostgreSQL) Limit(limit, offset int64) (s string) {
+	if limit > 0 {
+		s += ` LIMIT ` + fmt.Sprint(limit)
+	}
+	if offset >= 0 {
+		s += ` OFFSET ` + fmt.Sprint(offset)
+	}
+	return
+}
+
+func (_PostgreSQL) LastInsertID() bool {
+	return false
+}
+
+func (_PostgreSQL) Returning() bool {
+	return true
+}
+
+func (_PostgreSQL) MaxPlaceholders() int {
+	return 65535
+}
+
+func (d _PostgreSQL) Upsert(conflictColumns []string, sets string) string {
+	return onConflict(d, conflictColumns, sets)
+}
+
+func (_PostgreSQL) InsertIgnore(query string) string {
+	return query + ` ON CONFLICT DO NOTHING`
+}
+
+// _SQLStater is implemented by errors of both lib/pq and pgx.
+type _SQLStater interface {
+	SQLState() string
+}
+
+var reErr23505 = regexp.MustCompile(`unique constraint "([^"]+)"`)
+
+func (_PostgreSQL) TranslateError(err error) error {
+	var pgErr _SQLStater
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+	switch pgErr.SQLState() {
+	case "23505":
+		dke := &DupKeyError{}
+		if matches := reErr23505.FindStringSubmatch(err.Error()); matches != nil {
+			dke.Key = matches[1]
+		}
+		return &Error{Err: dke, Raw: err}
+	case "40001", "40P01":
+		// serialization_failure, deadlock_detected
+		return &Error{Err: &DeadlockError{}, Raw: err}
+	}
+	return nil
+}
+
+type _SQLite struct{}
+
+func (_SQLite) Name() string {
+	return "sqlite"
+}
+
+func (_SQLite) Placeholder(n int) string {
+	return "?"
+}
+
+func (_SQLite) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (_SQLite) Limit(limit, offset int64) (s string) {
+	if limit > 0 {
+		s += ` LIMIT ` + fmt.Sprint(limit)
+	} else if offset >= 0 {
+		s += ` LIMIT -1`
+	}
+	if offset >= 0 {
+		s += ` OFFSET ` + fmt.Sprint(offset)
+	}
+	return
+}
+
+func (_SQLite) LastInsertID() bool {
+	return true
+}
+
+// Returning requires SQLite 3.35.0 or later.
+func (_SQLite) Returning() bool {
+	return true
+}
+
+// MaxPlaceholders is SQLITE_MAX_VARIABLE_NUMBER since SQLite 3.32.0.
+func (_SQLite) MaxPlaceholders() int {
+	return 32766
+}
+
+func (d _SQLite) Upsert(conflictColumns []string, sets string) string {
+	return onConflict(d, conflictColumns, sets)
+}
+
+func (_SQLite) InsertIgnore(query string) string {
+	return query + ` ON CONFLICT DO NOTHING`
+}
+
+// Error messages are the same for both mattn/go-sqlite3 and modernc.org/sqlite.
+var reErrSQLiteUnique = regexp.MustCompile(`UNIQUE constraint failed: ([^ ]+)`)
+
+func (_SQLite) TranslateError(err error) error {
+	matches := reErrSQLiteUnique.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return nil
+	}
+	return &Error{
+		Err: &DupKeyError{Key: matches[1]},
+		Raw: err,
+	}
+}
@@ -0,0 +1,128 @@
+package taorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+type Order struct {
+	ID    int64
+	Group string
+}
+
+func (Order) TableName() string {
+	return `order`
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		d    Dialect
+		in   string
+		want string
+	}{
+		{MySQL, `a=? AND b=?`, `a=? AND b=?`},
+		{SQLite, `a=? AND b=?`, `a=? AND b=?`},
+		{PostgreSQL, `a=? AND b=?`, `a=$1 AND b=$2`},
+		{PostgreSQL, `a='?' AND b=?`, `a='?' AND b=$1`},
+		{PostgreSQL, `"a?"=? AND b=?`, `"a?"=$1 AND b=$2`},
+		{PostgreSQL, `data ?? 'k' AND data ??| ? AND b=?`, `data ? 'k' AND data ?| $1 AND b=$2`},
+		{PostgreSQL, `a='it\'s?' AND b=?`, `a='it\'s?' AND b=$1`},
+		{PostgreSQL, `a='it''s?' AND b="x""?" AND c=?`, `a='it''s?' AND b="x""?" AND c=$1`},
+		{PostgreSQL, `a='\\' AND b=?`, `a='\\' AND b=$1`},
+		{MySQL, `data ?? 'k' AND b=?`, `data ? 'k' AND b=?`},
+		{SQLite, `a='it\'s?' AND b=?`, `a='it\'s?' AND b=?`},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, rebind(test.d, test.in))
+	}
+}
+
+func TestDialectSQLs(t *testing.T) {
+	db := newTestDB(t)
+
+	pg := NewDBWithDialect(db, PostgreSQL)
+	lite := NewDBWithDialect(db, SQLite)
+	my := NewDB(db)
+
+	assertSQLs(t, []_SQLTest{
+		{
+			`SELECT * FROM users WHERE (id=$1 AND age>$2) LIMIT 10 OFFSET 20`,
+			pg.From(User{}).Where(`id=? AND age>?`, 1, 18).Limit(10).Offset(20).FindSQLRaw(),
+		},
+		{
+			`SELECT * FROM users WHERE (name IN ($1,$2)) OFFSET 20`,
+			pg.From(User{}).Where(`name IN (?)`, []string{`a`, `b`}).Offset(20).FindSQLRaw(),
+		},
+		{
+			`SELECT * FROM users WHERE (name ? 'a' AND age<>'?' AND age>$1)`,
+			pg.From(User{}).Where(`name ?? 'a' AND age<>'?' AND age>?`, 18).FindSQLRaw(),
+		},
+		{
+			`SELECT * FROM users LIMIT -1 OFFSET 20`,
+			lite.From(User{}).Offset(20).FindSQLRaw(),
+		},
+		{
+			`SELECT * FROM users`,
+			my.From(User{}).Offset(20).FindSQLRaw(),
+		},
+		{
+			"INSERT INTO `order` (`group`) VALUES ('g')",
+			my.Model(Order{Group: `g`}).CreateSQL(),
+		},
+		{
+			`INSERT INTO "order" ("group") VALUES ('g') RETURNING id`,
+			pg.Model(Order{Group: `g`}).CreateSQL(),
+		},
+		{
+			`UPDATE "order" SET "group"='g' WHERE (id=1)`,
+			pg.Model(Order{ID: 1}).UpdateModelSQL(Order{Group: `g`}),
+		},
+		{
+			`UPDATE "order" SET "group"='g' WHERE (id=1)`,
+			pg.Model(Order{ID: 1}).UpdateMapSQL(M{`group`: `g`}),
+		},
+		{
+			"UPDATE `order` SET `order`.`group`='g' WHERE (id=1)",
+			my.Model(Order{ID: 1}).UpdateMapSQL(M{"`order`.`group`": `g`}),
+		},
+		{
+			`INSERT INTO "order" (id,"group") VALUES (1,'g') ON CONFLICT (id) DO UPDATE SET "group"='h'`,
+			pg.Model(Order{ID: 1, Group: `g`}).UpsertSQL(nil, M{`group`: `h`}),
+		},
+		{
+			`DELETE FROM "order" WHERE (id=1)`,
+			pg.Model(Order{ID: 1}).DeleteSQL(),
+		},
+	})
+}
+
+type _PgError struct {
+	code string
+	msg  string
+}
+
+func (e *_PgError) Error() string    { return e.msg }
+func (e *_PgError) SQLState() string { return e.code }
+
+func TestTranslateError(t *testing.T) {
+	var dke *DupKeyError
+
+	err := WrapError(&mysql.MySQLError{Number: 1062, Message: `Duplicate entry 'tao' for key 'name'`})
+	assert.True(t, errors.As(err.(*Error).Err, &dke))
+	assert.Equal(t, `name`, dke.Key)
+	assert.Equal(t, `tao`, dke.Value)
+
+	err = WrapError(&_PgError{code: `23505`, msg: `pq: duplicate key value violates unique constraint "users_name_key"`})
+	assert.True(t, errors.As(err.(*Error).Err, &dke))
+	assert.Equal(t, `users_name_key`, dke.Key)
+
+	err = WrapError(errors.New(`UNIQUE constraint failed: users.name`))
+	assert.True(t, errors.As(err.(*Error).Err, &dke))
+	assert.Equal(t, `users.name`, dke.Key)
+
+	err = WrapError(errors.New(`something else`))
+	assert.Equal(t, ErrInternal, err.(*Error).Err)
+}
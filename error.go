@@ -5,13 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"regexp"
-
-	"github.com/go-sql-driver/mysql"
-)
-
-var (
-	reErr1062 = regexp.MustCompile(`Duplicate entry '([^']*)' for key '([^']+)'`)
+	"strings"
 )
 
 // Error all errors wrapper.
@@ -37,19 +31,9 @@ func WrapError(err error) error {
 		return err
 	}
 
-	// mysql errors
-	if myErr, ok := err.(*mysql.MySQLError); ok {
-		switch myErr.Number {
-		case 1062:
-			matches := reErr1062.FindStringSubmatch(myErr.Message)
-			return &Error{
-				Err: &DupKeyError{
-					Key:   matches[2],
-					Value: matches[1],
-				},
-				Raw: myErr,
-			}
-		}
+	// driver errors
+	if e := translateError(err); e != nil {
+		return e
 	}
 
 	// official error constants
@@ -60,7 +44,7 @@ func WrapError(err error) error {
 
 	// taorm error constants
 	switch err {
-	case ErrInternal, ErrNoWhere, ErrNoFields, ErrInvalidOut:
+	case ErrInternal, ErrNoWhere, ErrNoFields, ErrInvalidOut, ErrNotSupported:
 		return &Error{Err: ErrInternal, Raw: err}
 	}
 
@@ -68,8 +52,12 @@ func WrapError(err error) error {
 	switch err.(type) {
 	case *NoPlaceToSaveFieldError:
 		return &Error{Err: ErrInternal, Raw: err}
+	case *NoPlaceToSaveFieldsError:
+		return &Error{Err: ErrInternal, Raw: err}
 	case *NotStructError:
 		return &Error{Err: ErrInternal, Raw: err}
+	case *StaleObjectError:
+		return &Error{Err: err, Raw: err}
 	}
 
 	// unhandled errors
@@ -85,6 +73,8 @@ var (
 	ErrNoFields = errors.New("no fields")
 	// ErrInvalidOut ...
 	ErrInvalidOut = errors.New("invalid out")
+	// ErrNotSupported ...
+	ErrNotSupported = errors.New("not supported by dialect")
 )
 
 // NotFoundError ...
@@ -114,6 +104,16 @@ func (e NoPlaceToSaveFieldError) Error() string {
 	return fmt.Sprintf("NoPlaceToSaveFieldError: `%s'", e.Field)
 }
 
+// NoPlaceToSaveFieldsError is returned by ScanStrict with all the columns
+// that have no fields to scan into.
+type NoPlaceToSaveFieldsError struct {
+	Fields []string
+}
+
+func (e NoPlaceToSaveFieldsError) Error() string {
+	return fmt.Sprintf("NoPlaceToSaveFieldsError: `%s'", strings.Join(e.Fields, "', `"))
+}
+
 // NotStructError ...
 type NotStructError struct {
 	Kind reflect.Kind
@@ -123,6 +123,49 @@ func (e NotStructError) Error() string {
 	return fmt.Sprintf("taorm: not a struct: `%v'", e.Kind)
 }
 
+// DeadlockError is a deadlock, lock wait timeout or serialization failure.
+// The failed transaction can be retried.
+type DeadlockError struct {
+}
+
+func (e DeadlockError) Error() string {
+	return "deadlock or serialization failure"
+}
+
+// IsDeadlockError reports whether the transaction failed with err can be retried.
+func IsDeadlockError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var te *Error
+	if !errors.As(err, &te) {
+		te = WrapError(err).(*Error)
+	}
+	_, ok := te.Err.(*DeadlockError)
+	return ok
+}
+
+// StaleObjectError is returned by UpdateModel if the model has been updated
+// or deleted by others since it was read, detected by the version column.
+type StaleObjectError struct {
+	Table   string
+	Version int64
+}
+
+func (e StaleObjectError) Error() string {
+	return fmt.Sprintf("StaleObjectError: table=%s,version=%d", e.Table, e.Version)
+}
+
+// IsStaleObjectError reports whether err is a StaleObjectError.
+func IsStaleObjectError(err error) bool {
+	var te *Error
+	if errors.As(err, &te) {
+		err = te.Err
+	}
+	var se *StaleObjectError
+	return errors.As(err, &se)
+}
+
 // IsNotFoundError ...
 func IsNotFoundError(err error) bool {
 	if err == sql.ErrNoRows {
@@ -0,0 +1,114 @@
+package taorm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Find finds all rows of s as T, which is a struct or a pointer to struct.
+func Find[T any](s *Stmt) ([]T, error) {
+	return FindContext[T](context.Background(), s)
+}
+
+// FindContext ...
+func FindContext[T any](ctx context.Context, s *Stmt) ([]T, error) {
+	var out []T
+	if err := s.FindContext(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// First finds the first row of s as T, which is a struct or a primitive.
+//
+// A NotFoundError is returned if there are no rows.
+func First[T any](s *Stmt) (T, error) {
+	return FirstContext[T](context.Background(), s)
+}
+
+// FirstContext ...
+func FirstContext[T any](ctx context.Context, s *Stmt) (T, error) {
+	var out T
+	if err := s.Limit(1).FindContext(ctx, &out); err != nil {
+		var zero T
+		return zero, err
+	}
+	return out, nil
+}
+
+// Count counts rows of s.
+func Count(s *Stmt) (int64, error) {
+	return CountContext(context.Background(), s)
+}
+
+// CountContext ...
+func CountContext(ctx context.Context, s *Stmt) (int64, error) {
+	var n int64
+	if err := s.CountContext(ctx, &n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Repo is a repository of model T, which must be a struct with TableName.
+type Repo[T any] struct {
+	db *DB
+}
+
+// NewRepo creates a repository of T on db.
+func NewRepo[T any](db *DB) *Repo[T] {
+	return &Repo[T]{db: db}
+}
+
+// From returns a statement that selects from the table of T.
+func (r *Repo[T]) From() *Stmt {
+	return r.db.From(new(T))
+}
+
+// Get gets the model by values of its primary key, in the order of the
+// primary key columns.
+func (r *Repo[T]) Get(ctx context.Context, pk ...interface{}) (*T, error) {
+	info, err := getRegistered(new(T))
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	names := info.pkeyNames()
+	if len(names) == 0 || len(names) != len(pk) {
+		return nil, WrapError(fmt.Errorf("taorm: %d values for primary key (%s)", len(pk), strings.Join(names, ",")))
+	}
+	conds := make([]string, 0, len(names))
+	for _, name := range names {
+		conds = append(conds, quoteIdent(r.db.dialect, name)+"=?")
+	}
+	out, err := FirstContext[T](ctx, r.From().Where(strings.Join(conds, " AND "), pk...))
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// List lists models that satisfy all conds.
+func (r *Repo[T]) List(ctx context.Context, conds ..._Cond) ([]*T, error) {
+	return FindContext[*T](ctx, r.From().WhereCond(And(conds...)))
+}
+
+// Create creates model.
+func (r *Repo[T]) Create(ctx context.Context, model *T) error {
+	return r.db.Model(model).CreateContext(ctx)
+}
+
+// Update updates all columns of model by its primary key.
+func (r *Repo[T]) Update(ctx context.Context, model *T) error {
+	stmt := r.db.Model(model)
+	if _, ok := stmt.info.getPrimaryKey(model); !ok {
+		return WrapError(ErrNoWhere)
+	}
+	_, err := stmt.UpdateModelContext(ctx, model)
+	return err
+}
+
+// Delete deletes model by its primary key.
+func (r *Repo[T]) Delete(ctx context.Context, model *T) error {
+	return r.db.Model(model).DeleteContext(ctx)
+}
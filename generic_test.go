@@ -0,0 +1,66 @@
+package taorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneric(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+	ctx := context.Background()
+
+	mimic.SetRows([]string{"id", "name", "age"}, [][]driver.Value{
+		{int64(1), "tao", 18},
+		{int64(2), "yang", 20},
+	})
+
+	users, err := Find[User](tdb.From(User{}))
+	assert.NoError(t, err)
+	assert.Equal(t, []User{{1, `tao`, 18}, {2, `yang`, 20}}, users)
+
+	user, err := First[User](tdb.Where(`id=?`, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, User{1, `tao`, 18}, user)
+
+	mimic.SetRows([]string{"count"}, [][]driver.Value{{int64(2)}})
+	n, err := Count(tdb.From(User{}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	mimic.SetRows([]string{"id", "name", "age"}, nil)
+	_, err = First[User](tdb.From(User{}))
+	assert.True(t, IsNotFoundError(err))
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	repo := NewRepo[User](tdb)
+	_, err = repo.Get(ctx, 1)
+	assert.True(t, IsNotFoundError(err))
+	_, err = repo.Get(ctx)
+	assert.Error(t, err)
+	_, err = repo.List(ctx, Cond(`age>?`, 18), Cond(`name=?`, `tao`))
+	assert.NoError(t, err)
+	_, err = repo.List(ctx)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Create(ctx, &User{Name: `tao`}))
+	assert.NoError(t, repo.Update(ctx, &User{ID: 1, Name: `tao`}))
+	assert.Equal(t, ErrNoWhere, repo.Update(ctx, &User{Name: `tao`}).(*Error).Raw)
+	assert.NoError(t, repo.Delete(ctx, &User{ID: 1}))
+
+	assert.Equal(t, []string{
+		`SELECT * FROM users WHERE (id=?) LIMIT 1`,
+		`SELECT * FROM users WHERE ((age>?) AND (name=?))`,
+		`SELECT * FROM users`,
+		`INSERT INTO users (name,age) VALUES (?,?)`,
+		`UPDATE users SET name=?,age=? WHERE (id=?)`,
+		`DELETE FROM users WHERE (id=?)`,
+	}, *queries)
+}
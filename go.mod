@@ -12,4 +12,4 @@ require (
 	gopkg.in/yaml.v2 v2.2.2 // indirect
 )
 
-go 1.18
+go 1.21
@@ -0,0 +1,87 @@
+package taorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// _Hooks is a set of hooks implemented by a model.
+type _Hooks uint8
+
+const (
+	_BeforeCreate _Hooks = 1 << iota
+	_AfterCreate
+	_BeforeUpdate
+	_AfterFind
+	_BeforeDelete
+)
+
+var hookTypes = map[_Hooks]reflect.Type{
+	_BeforeCreate: reflect.TypeOf((*BeforeCreater)(nil)).Elem(),
+	_AfterCreate:  reflect.TypeOf((*AfterCreater)(nil)).Elem(),
+	_BeforeUpdate: reflect.TypeOf((*BeforeUpdater)(nil)).Elem(),
+	_AfterFind:    reflect.TypeOf((*AfterFinder)(nil)).Elem(),
+	_BeforeDelete: reflect.TypeOf((*BeforeDeleter)(nil)).Elem(),
+}
+
+// hooksOf returns hooks implemented by the struct type ty,
+// either by value or by pointer receivers.
+func hooksOf(ty reflect.Type) _Hooks {
+	var hooks _Hooks
+	ptr := reflect.PtrTo(ty)
+	for hook, iface := range hookTypes {
+		if ptr.Implements(iface) {
+			hooks |= hook
+		}
+	}
+	return hooks
+}
+
+// callHook calls hook on model if it is implemented.
+//
+// An error is returned if the hook has a pointer receiver but model is not
+// a pointer, rather than skipping the hook silently.
+func (s *_StructInfo) callHook(hook _Hooks, tx *DB, model interface{}) error {
+	if s.hooks&hook == 0 {
+		return nil
+	}
+	if ty := reflect.TypeOf(model); ty.Kind() != reflect.Ptr && !ty.Implements(hookTypes[hook]) {
+		return fmt.Errorf("taorm: model of %s must be a pointer to call its hooks", ty)
+	}
+	switch hook {
+	case _BeforeCreate:
+		if h, ok := model.(BeforeCreater); ok {
+			return h.BeforeCreate(tx)
+		}
+	case _AfterCreate:
+		if h, ok := model.(AfterCreater); ok {
+			return h.AfterCreate(tx)
+		}
+	case _BeforeUpdate:
+		if h, ok := model.(BeforeUpdater); ok {
+			return h.BeforeUpdate(tx)
+		}
+	case _AfterFind:
+		if h, ok := model.(AfterFinder); ok {
+			return h.AfterFind(tx)
+		}
+	case _BeforeDelete:
+		if h, ok := model.(BeforeDeleter); ok {
+			return h.BeforeDelete(tx)
+		}
+	}
+	return nil
+}
+
+// callHooks calls hook on each of models, and stops at the first error.
+func (s *_StructInfo) callHooks(hook _Hooks, tx *DB, models []interface{}) error {
+	if s.hooks&hook == 0 {
+		return nil
+	}
+	for _, model := range models {
+		if err := s.callHook(hook, tx, model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Post struct {
+	ID    int64
+	Title string
+	Slug  string
+
+	hooks []string
+}
+
+func (Post) TableName() string {
+	return `posts`
+}
+
+var errEmptyTitle = errors.New(`empty title`)
+
+func (p *Post) BeforeCreate(tx *DB) error {
+	p.hooks = append(p.hooks, `BeforeCreate`)
+	if p.Title == `` {
+		return errEmptyTitle
+	}
+	p.Slug = p.Title
+	return nil
+}
+
+func (p *Post) AfterCreate(tx *DB) error {
+	p.hooks = append(p.hooks, `AfterCreate`)
+	return nil
+}
+
+func (p *Post) BeforeUpdate(tx *DB) error {
+	p.hooks = append(p.hooks, `BeforeUpdate`)
+	return nil
+}
+
+func (p *Post) AfterFind(tx *DB) error {
+	p.hooks = append(p.hooks, `AfterFind`)
+	return nil
+}
+
+func (p *Post) BeforeDelete(tx *DB) error {
+	p.hooks = append(p.hooks, `BeforeDelete`)
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+	queries, stop := recordQueries()
+	defer stop()
+
+	post := Post{Title: `hello`}
+	assert.NoError(t, tdb.Model(&post).Create())
+	assert.Equal(t, `hello`, post.Slug)
+
+	_, err := tdb.Model(&post).Where(`id=?`, 1).UpdateModel(&post)
+	assert.NoError(t, err)
+	assert.NoError(t, tdb.Model(&post).Where(`id=?`, 1).Delete())
+	assert.Equal(t, []string{`BeforeCreate`, `AfterCreate`, `BeforeUpdate`, `BeforeDelete`}, post.hooks)
+
+	*queries = nil
+	err = tdb.Model(&Post{}).Create()
+	assert.Equal(t, errEmptyTitle, err.(*Error).Raw)
+	assert.Empty(t, *queries)
+
+	posts := []*Post{{Title: `a`}, {Title: `b`}}
+	assert.NoError(t, tdb.Model(posts).CreateBatch(0))
+	for _, p := range posts {
+		assert.Equal(t, []string{`BeforeCreate`, `AfterCreate`}, p.hooks)
+	}
+
+	values := []Post{{Title: `a`}, {Title: `b`}}
+	assert.NoError(t, tdb.Model(values).CreateBatch(0))
+	for _, p := range values {
+		assert.Equal(t, []string{`BeforeCreate`, `AfterCreate`}, p.hooks)
+	}
+
+	mimic.SetRows([]string{"id", "title"}, [][]driver.Value{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}})
+	var found []*Post
+	assert.NoError(t, tdb.From(Post{}).Find(&found))
+	assert.Len(t, found, 3)
+	for _, p := range found {
+		assert.Equal(t, []string{`AfterFind`}, p.hooks)
+	}
+
+	var foundValues []Post
+	assert.NoError(t, tdb.From(Post{}).Find(&foundValues))
+	assert.Len(t, foundValues, 3)
+	for _, p := range foundValues {
+		assert.Equal(t, []string{`AfterFind`}, p.hooks)
+	}
+	assert.Equal(t, `c`, foundValues[2].Title)
+
+	*queries = nil
+	err = tdb.Model(Post{Title: ``}).Create()
+	assert.Error(t, err)
+	err = tdb.Model(Post{ID: 1}).Delete()
+	assert.Error(t, err)
+	assert.Empty(t, *queries)
+}
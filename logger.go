@@ -0,0 +1,103 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// QueryLog is the log of an executed statement.
+type QueryLog struct {
+	Query        string        // the query sent to the database
+	Args         []interface{} // args of the query
+	Duration     time.Duration // time taken to execute the query
+	RowsAffected int64         // -1 if unknown, e.g.: for queries that return rows
+	Err          error         // the error returned by the driver
+}
+
+// Logger logs executed statements.
+type Logger interface {
+	LogQuery(ctx context.Context, log *QueryLog)
+}
+
+// WithLogger returns a copy of db that logs statements to logger.
+//
+// Transactions started from the returned db are logged too, including
+// BEGIN, COMMIT and ROLLBACK.
+func (db *DB) WithLogger(logger Logger) *DB {
+	c := *db
+	c.logger = logger
+	return &c
+}
+
+// logQuery logs the query executed since start.
+func (db *DB) logQuery(ctx context.Context, start time.Time, query string, args []interface{}, result sql.Result, err error) {
+	if db.logger == nil {
+		return
+	}
+	log := QueryLog{
+		Query:        query,
+		Args:         args,
+		Duration:     time.Since(start),
+		RowsAffected: -1,
+		Err:          err,
+	}
+	if result != nil {
+		if n, err := result.RowsAffected(); err == nil {
+			log.RowsAffected = n
+		}
+	}
+	db.logger.LogQuery(ctx, &log)
+}
+
+// SlogLogger logs statements to a slog.Logger.
+//
+// Failed statements are logged at error level, slow statements are logged at
+// warning level, and others are logged at debug level.
+type SlogLogger struct {
+	Logger *slog.Logger
+
+	// SlowThreshold is the duration after which a statement is considered slow.
+	// No statements are considered slow if zero.
+	SlowThreshold time.Duration
+}
+
+// NewSlogLogger creates a Logger that logs to logger.
+// If logger is nil, slog.Default() is used.
+func NewSlogLogger(logger *slog.Logger, slowThreshold time.Duration) *SlogLogger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SlogLogger{
+		Logger:        logger,
+		SlowThreshold: slowThreshold,
+	}
+}
+
+// LogQuery implements Logger.
+func (l *SlogLogger) LogQuery(ctx context.Context, log *QueryLog) {
+	level := slog.LevelDebug
+	msg := "taorm: query"
+	switch {
+	case log.Err != nil:
+		level = slog.LevelError
+		msg = "taorm: query failed"
+	case l.SlowThreshold > 0 && log.Duration >= l.SlowThreshold:
+		level = slog.LevelWarn
+		msg = "taorm: slow query"
+	}
+	if !l.Logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("query", log.Query),
+		slog.Any("args", log.Args),
+		slog.Duration("duration", log.Duration),
+		slog.Int64("rows_affected", log.RowsAffected),
+	}
+	if log.Err != nil {
+		attrs = append(attrs, slog.Any("error", log.Err))
+	}
+	l.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,65 @@
+package taorm
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type _TestLogger struct {
+	logs []QueryLog
+}
+
+func (l *_TestLogger) LogQuery(ctx context.Context, log *QueryLog) {
+	l.logs = append(l.logs, *log)
+}
+
+func TestLogger(t *testing.T) {
+	db := newTestDB(t)
+
+	logger := &_TestLogger{}
+	tdb := NewDB(db).WithLogger(logger)
+
+	err := tdb.TxCall(func(tx *DB) error {
+		return tx.Model(&User{Name: `tao`}).Create()
+	})
+	assert.NoError(t, err)
+
+	var users []*User
+	assert.NoError(t, tdb.From(User{}).Where(`age>?`, 1).Find(&users))
+
+	queries := []string{}
+	for _, log := range logger.logs {
+		queries = append(queries, log.Query)
+	}
+	assert.Equal(t, []string{
+		`BEGIN`,
+		`INSERT INTO users (name,age) VALUES (?,?)`,
+		`COMMIT`,
+		`SELECT * FROM users WHERE (age>?)`,
+	}, queries)
+	assert.Equal(t, []interface{}{`tao`, 0}, logger.logs[1].Args)
+	assert.Equal(t, int64(0), logger.logs[1].RowsAffected)
+	assert.Equal(t, int64(-1), logger.logs[3].RowsAffected)
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+	logger := NewSlogLogger(slog.New(handler), time.Second)
+
+	logger.LogQuery(context.Background(), &QueryLog{Query: `fast`, Duration: time.Millisecond})
+	logger.LogQuery(context.Background(), &QueryLog{Query: `slow`, Duration: 2 * time.Second})
+	logger.LogQuery(context.Background(), &QueryLog{Query: `failed`, Err: sql.ErrNoRows})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `level=WARN msg="taorm: slow query" query=slow`)
+	assert.Contains(t, lines[1], `level=ERROR msg="taorm: query failed" query=failed`)
+}
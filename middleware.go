@@ -0,0 +1,121 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Operation is the kind of operation that issues a statement.
+type Operation string
+
+// Operations.
+const (
+	OpCreate Operation = "create" // Stmt.Create and its variants
+	OpFind   Operation = "find"   // Stmt.Find
+	OpCount  Operation = "count"  // Stmt.Count
+	OpUpdate Operation = "update" // Stmt.UpdateMap, Stmt.UpdateModel and their variants
+	OpDelete Operation = "delete" // Stmt.Delete and its variants
+	OpTx     Operation = "tx"     // BEGIN, COMMIT, ROLLBACK and savepoints of TxCall
+	OpRaw    Operation = "raw"    // DB.Exec, DB.Query and ScanRows
+)
+
+// Query is a statement to be executed.
+type Query struct {
+	Op    Operation     // the operation that issues the statement
+	Table string        // the main table, empty if unknown
+	SQL   string        // the query with bind variables of the dialect
+	Args  []interface{} // args of the query
+}
+
+// Executor executes statements.
+type Executor interface {
+	Exec(ctx context.Context, q *Query) (sql.Result, error)
+	Query(ctx context.Context, q *Query) (*sql.Rows, error)
+
+	// Tx begins, commits or rolls back a transaction by do, which is done by
+	// the sql package instead of executing q.SQL, which is just BEGIN, COMMIT
+	// or ROLLBACK for middlewares to know. Savepoints are executed by Exec.
+	Tx(ctx context.Context, q *Query, do func() error) error
+}
+
+// Middleware wraps an Executor to do something around the execution,
+// e.g.: tracing, metrics, or rejecting the statement by returning an error.
+type Middleware func(next Executor) Executor
+
+// Use returns a copy of db with middlewares added, db is left unchanged.
+//
+// Middlewares are called in the order they are added, and are also used by
+// transactions started from the returned db.
+func (db *DB) Use(middlewares ...Middleware) *DB {
+	c := *db
+	n := len(db.middlewares)
+	c.middlewares = append(db.middlewares[:n:n], middlewares...)
+	return &c
+}
+
+// _Executor executes statements by the underlying sql.DB or sql.Tx.
+type _Executor struct {
+	db *DB
+}
+
+func (e _Executor) Exec(ctx context.Context, q *Query) (sql.Result, error) {
+	start := time.Now()
+	result, err := e.db._SQLCommon.ExecContext(ctx, q.SQL, q.Args...)
+	e.db.logQuery(ctx, start, q.SQL, q.Args, result, err)
+	return result, err
+}
+
+func (e _Executor) Query(ctx context.Context, q *Query) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := e.db._SQLCommon.QueryContext(ctx, q.SQL, q.Args...)
+	e.db.logQuery(ctx, start, q.SQL, q.Args, nil, err)
+	return rows, err
+}
+
+func (e _Executor) Tx(ctx context.Context, q *Query, do func() error) error {
+	start := time.Now()
+	err := do()
+	e.db.logQuery(ctx, start, q.SQL, q.Args, nil, err)
+	return err
+}
+
+// executor returns the executor wrapped by all middlewares.
+func (db *DB) executor() Executor {
+	var e Executor = _Executor{db: db}
+	for i := len(db.middlewares) - 1; i >= 0; i-- {
+		e = db.middlewares[i](e)
+	}
+	return e
+}
+
+// exec executes query issued by op on table.
+func (db *DB) exec(ctx context.Context, op Operation, table string, query string, args ...interface{}) (sql.Result, error) {
+	q := Query{
+		Op:    op,
+		Table: table,
+		SQL:   rebind(db.dialect, query),
+		Args:  args,
+	}
+	return db.executor().Exec(ctx, &q)
+}
+
+// execTx executes the transaction control query by do.
+func (db *DB) execTx(ctx context.Context, query string, do func() error) error {
+	q := Query{
+		Op:  OpTx,
+		SQL: query,
+	}
+	return db.executor().Tx(ctx, &q, do)
+}
+
+// query executes query issued by op on table.
+func (db *DB) query(ctx context.Context, op Operation, table string, query string, args ...interface{}) (*sql.Rows, error) {
+	q := Query{
+		Op:    op,
+		Table: table,
+		SQL:   rebind(db.dialect, query),
+		Args:  args,
+	}
+	return db.executor().Query(ctx, &q)
+}
@@ -0,0 +1,125 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type _TestExecutor struct {
+	next     Executor
+	queries  *[]Query
+	reject   error
+	rejectOp Operation // OpDelete if empty
+}
+
+func (e _TestExecutor) Exec(ctx context.Context, q *Query) (sql.Result, error) {
+	*e.queries = append(*e.queries, *q)
+	op := e.rejectOp
+	if op == "" {
+		op = OpDelete
+	}
+	if q.Op == op && e.reject != nil {
+		return nil, e.reject
+	}
+	return e.next.Exec(ctx, q)
+}
+
+func (e _TestExecutor) Tx(ctx context.Context, q *Query, do func() error) error {
+	*e.queries = append(*e.queries, *q)
+	if q.Op == e.rejectOp && e.reject != nil {
+		return e.reject
+	}
+	return e.next.Tx(ctx, q, do)
+}
+
+func (e _TestExecutor) Query(ctx context.Context, q *Query) (*sql.Rows, error) {
+	*e.queries = append(*e.queries, *q)
+	return e.next.Query(ctx, q)
+}
+
+// _CopyExecutor passes copies of queries to next.
+type _CopyExecutor struct {
+	next Executor
+}
+
+func (e _CopyExecutor) Exec(ctx context.Context, q *Query) (sql.Result, error) {
+	c := *q
+	return e.next.Exec(ctx, &c)
+}
+
+func (e _CopyExecutor) Query(ctx context.Context, q *Query) (*sql.Rows, error) {
+	c := *q
+	return e.next.Query(ctx, &c)
+}
+
+func (e _CopyExecutor) Tx(ctx context.Context, q *Query, do func() error) error {
+	c := *q
+	return e.next.Tx(ctx, &c, do)
+}
+
+func TestMiddleware(t *testing.T) {
+	db := newTestDB(t)
+
+	var queries []Query
+	errRejected := errors.New(`rejected`)
+	tdb := NewDB(db).Use(func(next Executor) Executor {
+		return _TestExecutor{next: next, queries: &queries, reject: errRejected}
+	})
+
+	err := tdb.TxCall(func(tx *DB) error {
+		if err := tx.Model(&User{Name: `tao`}).Create(); err != nil {
+			return err
+		}
+		return tx.TxCall(func(tx *DB) error {
+			_, err := tx.From(User{}).Where(`id=?`, 1).UpdateMap(M{`age`: 20})
+			return err
+		})
+	})
+	assert.NoError(t, err)
+
+	var users []*User
+	assert.NoError(t, tdb.From(User{}).Find(&users))
+
+	err = tdb.From(User{}).Where(`id=?`, 1).Delete()
+	assert.Equal(t, errRejected, err.(*Error).Raw)
+
+	ops := []Operation{}
+	tables := []string{}
+	for _, q := range queries {
+		ops = append(ops, q.Op)
+		tables = append(tables, q.Table)
+	}
+	assert.Equal(t, []Operation{OpTx, OpCreate, OpTx, OpUpdate, OpTx, OpTx, OpFind, OpDelete}, ops)
+	assert.Equal(t, []string{``, `users`, ``, `users`, ``, ``, `users`, `users`}, tables)
+	assert.Equal(t, `BEGIN`, queries[0].SQL)
+	assert.Equal(t, `SAVEPOINT taorm_sp_1`, queries[2].SQL)
+	assert.Equal(t, `COMMIT`, queries[5].SQL)
+
+	err = NewDB(db).Use(func(next Executor) Executor {
+		return _TestExecutor{next: next, queries: &queries, reject: errRejected, rejectOp: OpTx}
+	}).TxCall(func(tx *DB) error {
+		t.Fatal(`called`)
+		return nil
+	})
+	assert.Equal(t, errRejected, err.(*Error).Raw)
+
+	plain := NewDB(db)
+	plain.Use(func(next Executor) Executor {
+		return _TestExecutor{next: next, queries: &queries, reject: errRejected}
+	})
+	assert.NoError(t, plain.From(User{}).Where(`id=?`, 1).Delete())
+
+	called := false
+	err = NewDB(db).Use(func(next Executor) Executor {
+		return _CopyExecutor{next: next}
+	}).TxCall(func(tx *DB) error {
+		called = true
+		return tx.Model(&User{Name: `tao`}).Create()
+	})
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
@@ -13,3 +13,36 @@ func SetRows(columns []string, values [][]driver.Value) {
 	_columns = columns
 	_values = values
 }
+
+var _rowsFunc func(query string) ([]string, [][]driver.Value)
+
+// SetRowsFunc sets a function that returns the rows of each query,
+// which overrides rows set by SetRows.
+//
+// It is disabled if fn is nil.
+func SetRowsFunc(fn func(query string) (columns []string, values [][]driver.Value)) {
+	_rowsFunc = fn
+}
+
+var _rowsAffected int64
+
+// SetRowsAffected sets the number of rows affected by every statement executed.
+func SetRowsAffected(n int64) {
+	_rowsAffected = n
+}
+
+var _recorder func(query string)
+
+// SetRecorder sets a function that is called with every statement
+// executed, including "BEGIN", "COMMIT" and "ROLLBACK" of transactions.
+//
+// Recording is disabled if recorder is nil.
+func SetRecorder(recorder func(query string)) {
+	_recorder = recorder
+}
+
+func record(query string) {
+	if _recorder != nil {
+		_recorder(query)
+	}
+}
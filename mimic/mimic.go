@@ -1,6 +1,7 @@
 package mimic
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"io"
@@ -26,10 +27,13 @@ type Conn struct {
 }
 
 var _ driver.Conn = &Conn{}
+var _ driver.ConnBeginTx = &Conn{}
+var _ driver.ExecerContext = &Conn{}
+var _ driver.QueryerContext = &Conn{}
 
 // Prepare ...
 func (c *Conn) Prepare(query string) (driver.Stmt, error) {
-	return &Stmt{}, nil
+	return &Stmt{query: query}, nil
 }
 
 // Close ...
@@ -39,14 +43,54 @@ func (c *Conn) Close() error {
 
 // Begin ...
 func (c *Conn) Begin() (driver.Tx, error) {
-	panic("tx not supported")
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx ...
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	record("BEGIN")
+	return &Tx{}, nil
+}
+
+// ExecContext ...
+func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return (&Stmt{query: query}).ExecContext(ctx, args)
+}
+
+// QueryContext ...
+func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return (&Stmt{query: query}).QueryContext(ctx, args)
+}
+
+// Tx implements driver.Tx.
+type Tx struct {
+}
+
+var _ driver.Tx = &Tx{}
+
+// Commit ...
+func (t *Tx) Commit() error {
+	record("COMMIT")
+	return nil
+}
+
+// Rollback ...
+func (t *Tx) Rollback() error {
+	record("ROLLBACK")
+	return nil
 }
 
 // Stmt  implements driver.Stmt.
 type Stmt struct {
+	query string
 }
 
 var _ driver.Stmt = &Stmt{}
+var _ driver.StmtExecContext = &Stmt{}
+var _ driver.StmtQueryContext = &Stmt{}
 
 // Close ...
 func (s *Stmt) Close() error {
@@ -60,12 +104,32 @@ func (s *Stmt) NumInput() int {
 
 // Exec ...
 func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	record(s.query)
 	return &Result{}, nil
 }
 
 // Query ...
 func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
-	return &Rows{}, nil
+	record(s.query)
+	return newRows(s.query), nil
+}
+
+// ExecContext ...
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	record(s.query)
+	return &Result{}, nil
+}
+
+// QueryContext ...
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	record(s.query)
+	return newRows(s.query), nil
 }
 
 type Result struct {
@@ -78,17 +142,27 @@ func (r *Result) LastInsertId() (int64, error) {
 }
 
 func (r *Result) RowsAffected() (int64, error) {
-	return 0, nil
+	return _rowsAffected, nil
 }
 
 type Rows struct {
-	index int
+	columns []string
+	values  [][]driver.Value
+	index   int
 }
 
 var _ driver.Rows = &Rows{}
 
+func newRows(query string) *Rows {
+	if _rowsFunc != nil {
+		columns, values := _rowsFunc(query)
+		return &Rows{columns: columns, values: values}
+	}
+	return &Rows{columns: _columns, values: _values}
+}
+
 func (r *Rows) Columns() []string {
-	return _columns
+	return r.columns
 }
 
 func (r *Rows) Close() error {
@@ -96,11 +170,11 @@ func (r *Rows) Close() error {
 }
 
 func (r *Rows) Next(dest []driver.Value) error {
-	if r.index >= len(_values) {
+	if r.index >= len(r.values) {
 		return io.EOF
 	}
 	for i, n := 0, len(dest); i < n; i++ {
-		dest[i] = _values[r.index][i]
+		dest[i] = r.values[r.index][i]
 	}
 	r.index++
 	return nil
@@ -0,0 +1,128 @@
+package taorm
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// _Nested is a struct field tagged with `nested` to scan columns of a
+// joined table into, which are selected with aliases like `prefix__column`.
+//
+// The prefix defaults to the table name of the struct, and can be set by
+// `nested:prefix`, where prefix is the name or alias of the table in the query.
+// If the field is a pointer, it is set to nil if all its columns are NULL,
+// e.g.: for rows without matches of LEFT JOIN.
+type _Nested struct {
+	field   _FieldInfo   // the field in the outer struct
+	elem    reflect.Type // the struct type
+	prefix  string       // the prefix of column aliases
+	columns []string     // columns of the struct
+}
+
+// addNested adds columns of the nested struct field with prefix.
+func (s *_StructInfo) addNested(field reflect.StructField, prefix string) error {
+	ty := field.Type
+	isPtr := ty.Kind() == reflect.Ptr
+	if isPtr {
+		ty = ty.Elem()
+	}
+	if ty.Kind() != reflect.Struct {
+		return fmt.Errorf("taorm: nested field %s must be a struct", field.Name)
+	}
+	if prefix == "" {
+		name, err := getTableNameFromType(ty)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return fmt.Errorf("taorm: nested field %s has no prefix", field.Name)
+		}
+		prefix = name
+	}
+
+	inner := newStructInfo()
+	columns := []_FieldInfo{}
+	if err := addStructFields(inner, ty, &columns); err != nil {
+		return err
+	}
+	if len(inner.nested) > 0 {
+		return fmt.Errorf("taorm: nested field %s cannot have nested fields", field.Name)
+	}
+
+	n := &_Nested{
+		field: _FieldInfo{
+			offset: field.Offset,
+			_type:  field.Type,
+			name:   field.Name,
+		},
+		elem:   ty,
+		prefix: prefix,
+	}
+	for _, c := range columns {
+		n.columns = append(n.columns, c.name)
+		fi := c
+		fi.name = prefix + "__" + c.name
+		fi.pk = false
+		if isPtr {
+			fi.nested = n
+		} else {
+			fi.offset += field.Offset
+		}
+		s.fields[fi.name] = fi
+	}
+	s.nested = append(s.nested, n)
+	s.hasNestedPtr = s.hasNestedPtr || isPtr
+	return nil
+}
+
+// setNested sets pointer nested structs of out by values scanned into ptrs,
+// or nil if all the values are NULL.
+func (s *_StructInfo) setNested(out interface{}, layout []_FieldInfo, ptrs []interface{}) {
+	values := make(map[*_Nested]reflect.Value)
+	for i, fi := range layout {
+		if fi.nested == nil {
+			continue
+		}
+		value, ok := values[fi.nested]
+		if !ok {
+			value = reflect.Zero(fi.nested.field._type)
+			values[fi.nested] = value
+		}
+		var p reflect.Value
+		if d, ok := ptrs[i].(_Deserializer); ok {
+			p = d.ptr.Elem()
+		} else {
+			p = reflect.ValueOf(ptrs[i]).Elem()
+		}
+		if p.IsNil() {
+			continue
+		}
+		if value.IsNil() {
+			value = reflect.New(fi.nested.elem)
+			values[fi.nested] = value
+		}
+		reflect.NewAt(fi._type, unsafe.Add(value.UnsafePointer(), fi.offset)).Elem().Set(p.Elem())
+	}
+	for n, value := range values {
+		s.valueOf(out, n.field).Set(value)
+	}
+}
+
+// nestedSelect returns the select list of the struct with nested structs.
+// Columns of the struct itself are qualified by table, which is quoted
+// the same way as prefixes of nested structs.
+func (s *_StructInfo) nestedSelect(d Dialect, table string) []string {
+	fields := []string{}
+	for _, name := range append(s.pkeyNames(), s.insertNames...) {
+		fields = append(fields, quoteIdent(d, table)+"."+quoteIdent(d, name))
+	}
+	for _, n := range s.nested {
+		for _, c := range n.columns {
+			fields = append(fields, fmt.Sprintf(`%s.%s AS %s`,
+				quoteIdent(d, n.prefix), quoteIdent(d, c), quoteIdent(d, n.prefix+"__"+c),
+			))
+		}
+	}
+	return fields
+}
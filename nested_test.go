@@ -0,0 +1,88 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type AuthorBook struct {
+	Author `taorm:"nested"`
+	Book   *Book `taorm:"nested:b"`
+}
+
+func TestNested(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	mimic.SetRows(
+		[]string{"authors__id", "authors__name", "b__id", "b__author_id", "b__title"},
+		[][]driver.Value{
+			{int64(1), "tao", int64(10), int64(1), "a"},
+			{int64(2), "yang", nil, nil, nil},
+			{int64(1), "tao", int64(11), int64(1), "b"},
+		},
+	)
+
+	var rows []AuthorBook
+	assert.NoError(t, tdb.From(Author{}).LeftJoin(`books AS b`, `b.author_id=authors.id`).Find(&rows))
+	assert.Equal(t, []string{
+		`SELECT authors.id AS authors__id,authors.name AS authors__name,` +
+			`b.id AS b__id,b.author_id AS b__author_id,b.title AS b__title ` +
+			`FROM authors LEFT JOIN books AS b ON b.author_id=authors.id`,
+	}, *queries)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, `tao`, rows[0].Name)
+	assert.Equal(t, &Book{ID: 10, AuthorID: 1, Title: `a`}, rows[0].Book)
+	assert.Equal(t, `yang`, rows[1].Name)
+	assert.Nil(t, rows[1].Book)
+	assert.Equal(t, `b`, rows[2].Book.Title)
+
+	var row AuthorBook
+	assert.NoError(t, tdb.From(Author{}).LeftJoin(`books AS b`, `b.author_id=authors.id`).Find(&row))
+	assert.Equal(t, int64(1), row.ID)
+	assert.Equal(t, int64(10), row.Book.ID)
+}
+
+type OrderAuthor struct {
+	ID     int64
+	Author *Author `taorm:"nested:a"`
+}
+
+func TestNestedQuote(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDBWithDialect(db, PostgreSQL)
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	mimic.SetRows([]string{"id", "a__id", "a__name"}, [][]driver.Value{{int64(1), int64(2), "tao"}})
+
+	var rows []OrderAuthor
+	assert.NoError(t, tdb.From(Order{}).LeftJoin(`authors AS a`, `a.id="order".id`).Find(&rows))
+	assert.Equal(t, `tao`, rows[0].Author.Name)
+
+	mimic.SetRows([]string{"id"}, [][]driver.Value{{int64(1)}})
+	assert.NoError(t, tdb.From(Order{}).LeftJoin(`authors AS a`, `a.id="order".id`).Select(`"order".id`).Find(&rows))
+
+	assert.Equal(t, []string{
+		`SELECT "order".id,a.id AS a__id,a.name AS a__name FROM "order" LEFT JOIN authors AS a ON a.id="order".id`,
+		`SELECT "order".id FROM "order" LEFT JOIN authors AS a ON a.id="order".id`,
+	}, *queries)
+}
+
+type NestedAuthorBook struct {
+	AuthorBook `taorm:"nested:ab"`
+}
+
+func TestNestedNested(t *testing.T) {
+	_, err := getRegistered(NestedAuthorBook{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Profile struct {
+	ID       int64
+	Nickname *string
+	Age      *int64
+	Birthday *time.Time
+	Email    _Null[string]
+}
+
+func (Profile) TableName() string {
+	return `profiles`
+}
+
+func TestNullable(t *testing.T) {
+	db := newTestDB(t)
+
+	logger := &_TestLogger{}
+	tdb := NewDB(db).WithLogger(logger)
+
+	nickname := `tao`
+	assert.Equal(t,
+		`INSERT INTO profiles (nickname,age,birthday,email) VALUES ('tao',NULL,NULL,NULL)`,
+		tdb.Model(Profile{Nickname: &nickname}).CreateSQL(),
+	)
+
+	assert.NoError(t, tdb.Model(&Profile{Nickname: &nickname, Email: _Null[string]{`t@example.com`, true}}).Create())
+	assert.Equal(t, []interface{}{&nickname, (*int64)(nil), (*time.Time)(nil), _Null[string]{`t@example.com`, true}}, logger.logs[0].Args)
+
+	mimic.SetRows([]string{"id", "nickname", "age", "birthday", "email"}, [][]driver.Value{
+		{int64(1), nil, int64(18), nil, nil},
+		{int64(2), "yang", nil, nil, "y@example.com"},
+	})
+	var profiles []Profile
+	assert.NoError(t, tdb.From(Profile{}).Find(&profiles))
+	assert.Len(t, profiles, 2)
+	assert.Nil(t, profiles[0].Nickname)
+	assert.Equal(t, int64(18), *profiles[0].Age)
+	assert.Nil(t, profiles[0].Birthday)
+	assert.False(t, profiles[0].Email.Valid)
+	assert.Equal(t, `yang`, *profiles[1].Nickname)
+	assert.Nil(t, profiles[1].Age)
+	assert.Equal(t, _Null[string]{`y@example.com`, true}, profiles[1].Email)
+}
+
+// _Tags implements driver.Valuer by pointer receiver.
+type _Tags struct {
+	list []string
+}
+
+func (t *_Tags) Value() (driver.Value, error) {
+	return strings.Join(t.list, ","), nil
+}
+
+func (t *_Tags) Scan(value interface{}) error {
+	t.list = strings.Split(string(value.([]byte)), ",")
+	return nil
+}
+
+type Badge struct {
+	ID   int64
+	Tags _Tags
+}
+
+func (Badge) TableName() string {
+	return `badges`
+}
+
+func TestPtrValuer(t *testing.T) {
+	db := newTestDB(t)
+
+	logger := &_TestLogger{}
+	tdb := NewDB(db).WithLogger(logger)
+
+	badge := Badge{Tags: _Tags{[]string{`a`, `b`}}}
+	assert.NoError(t, tdb.Model(&badge).Create())
+	assert.Equal(t, `INSERT INTO badges (tags) VALUES (?)`, logger.logs[0].Query)
+	assert.Equal(t, []interface{}{&badge.Tags}, logger.logs[0].Args)
+	value, err := logger.logs[0].Args[0].(driver.Valuer).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `a,b`, value)
+}
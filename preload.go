@@ -0,0 +1,346 @@
+package taorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// _Assoc is an association of a struct to other structs, which can be
+// loaded by Preload.
+//
+//   - `hasMany:fk`: []T or []*T, whose rows reference the owner by column fk.
+//   - `belongsTo:fk`: T or *T, which is referenced by column fk of the owner.
+//   - `many2many:table`: []T or []*T, which are referenced with the owner by
+//     rows of the join table. Columns of the join table default to
+//     owner_id and t_id by snake-cased type names, and can be set by
+//     `many2many:table:owner_id:t_id`.
+type _Assoc struct {
+	kind       string       // hasMany, belongsTo or many2many
+	field      _FieldInfo   // the field to save associated structs
+	elem       reflect.Type // the type of associated structs
+	foreignKey string       // the column of hasMany or belongsTo
+	joinTable  string       // the join table of many2many
+	joinKey    string       // the column of the join table referencing the owner
+	joinRefKey string       // the column of the join table referencing associated structs
+}
+
+// getAssoc gets the association declared by field of struct type owner,
+// or nil if there is none.
+func getAssoc(owner reflect.Type, field reflect.StructField) (*_Assoc, error) {
+	a := &_Assoc{
+		field: _FieldInfo{
+			offset: field.Offset,
+			_type:  field.Type,
+			name:   field.Name,
+		},
+	}
+	many := false
+	if fk, ok := getTagOption(field, "hasMany"); ok {
+		a.kind, a.foreignKey, many = "hasMany", fk, true
+	} else if fk, ok := getTagOption(field, "belongsTo"); ok {
+		a.kind, a.foreignKey = "belongsTo", fk
+	} else if join, ok := getTagOption(field, "many2many"); ok {
+		a.kind, many = "many2many", true
+		parts := strings.Split(join, ":")
+		a.joinTable = parts[0]
+		if len(parts) == 3 {
+			a.joinKey, a.joinRefKey = parts[1], parts[2]
+		}
+	} else {
+		return nil, nil
+	}
+
+	ty := field.Type
+	if many {
+		if ty.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("taorm: association %s must be a slice", field.Name)
+		}
+		ty = ty.Elem()
+	}
+	if ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	if ty.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("taorm: association %s must be of structs", field.Name)
+	}
+	a.elem = ty
+
+	if a.kind != "many2many" && a.foreignKey == "" || a.kind == "many2many" && a.joinTable == "" {
+		return nil, fmt.Errorf("taorm: association %s has no keys", field.Name)
+	}
+	if a.kind == "many2many" && a.joinKey == "" {
+		a.joinKey = toSnakeCase(owner.Name()) + "_id"
+		a.joinRefKey = toSnakeCase(ty.Name()) + "_id"
+	}
+
+	return a, nil
+}
+
+// Preload makes Find load the associations named by fields of the struct,
+// by one query for each association, or more if there are more keys than
+// bind variables allowed by the dialect.
+//
+// Preload is not supported by Rows and Each, which return an error.
+func (s *Stmt) Preload(fields ...string) *Stmt {
+	s.preloads = append(s.preloads, fields...)
+	return s
+}
+
+// preload loads associations into out found by Find.
+func (s *Stmt) preload(ctx context.Context, out interface{}) error {
+	if len(s.preloads) == 0 {
+		return nil
+	}
+	info, err := getRegistered(out)
+	if err != nil {
+		return err
+	}
+	var owners []interface{}
+	if reflect.TypeOf(out).Elem().Kind() == reflect.Slice {
+		owners = sliceElems(out)
+	} else {
+		owners = []interface{}{out}
+	}
+	if len(owners) == 0 {
+		return nil
+	}
+	for _, name := range s.preloads {
+		a, ok := info.assocs[name]
+		if !ok {
+			return fmt.Errorf("taorm: no association named %s", name)
+		}
+		if err := a.load(ctx, s.db, info, owners); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyOf returns v as a key to match associated structs.
+func keyOf(v interface{}) string {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		v = rv.Elem().Interface()
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		v, _ = valuer.Value()
+	}
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// keysOf returns distinct non-zero values of field of models.
+func keysOf(info *_StructInfo, field _FieldInfo, models []interface{}) []interface{} {
+	keys := []interface{}{}
+	seen := map[string]bool{}
+	for _, m := range models {
+		v := info.valueOf(m, field)
+		if v.IsZero() {
+			continue
+		}
+		k := keyOf(v.Interface())
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, v.Interface())
+		}
+	}
+	return keys
+}
+
+// singlePrimaryKey returns the primary key of info, which must be a single column.
+func singlePrimaryKey(info *_StructInfo) (_FieldInfo, error) {
+	if !info.hasSinglePrimaryKey() {
+		return _FieldInfo{}, fmt.Errorf("taorm: associations require a single-column primary key: %s", info.tableName)
+	}
+	return info.pkeyFields[0], nil
+}
+
+func (a *_Assoc) load(ctx context.Context, db *DB, info *_StructInfo, owners []interface{}) error {
+	elemInfo, err := getRegistered(reflect.New(a.elem).Interface())
+	if err != nil {
+		return err
+	}
+	switch a.kind {
+	case "hasMany":
+		return a.loadHasMany(ctx, db, info, elemInfo, owners)
+	case "belongsTo":
+		return a.loadBelongsTo(ctx, db, info, elemInfo, owners)
+	default:
+		return a.loadMany2Many(ctx, db, info, elemInfo, owners)
+	}
+}
+
+// find finds associated structs whose column is in keys.
+//
+// keys are split into chunks if there are more than MaxPlaceholders of the dialect.
+func (a *_Assoc) find(ctx context.Context, db *DB, column string, keys []interface{}) ([]interface{}, error) {
+	var models []interface{}
+	size := db.dialect.MaxPlaceholders()
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		out := reflect.New(reflect.SliceOf(reflect.PtrTo(a.elem)))
+		err := db.From(reflect.New(a.elem).Interface()).
+			Where(quoteIdent(db.dialect, column)+" IN (?)", keys[start:end]).
+			FindContext(ctx, out.Interface())
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < out.Elem().Len(); i++ {
+			models = append(models, out.Elem().Index(i).Interface())
+		}
+	}
+	return models, nil
+}
+
+// setMany sets the associated structs of owner.
+func (a *_Assoc) setMany(info *_StructInfo, owner interface{}, models []interface{}) {
+	slice := reflect.MakeSlice(a.field._type, 0, len(models))
+	isPtr := a.field._type.Elem().Kind() == reflect.Ptr
+	for _, m := range models {
+		v := reflect.ValueOf(m)
+		if !isPtr {
+			v = v.Elem()
+		}
+		slice = reflect.Append(slice, v)
+	}
+	info.valueOf(owner, a.field).Set(slice)
+}
+
+func (a *_Assoc) loadHasMany(ctx context.Context, db *DB, info, elemInfo *_StructInfo, owners []interface{}) error {
+	pk, err := singlePrimaryKey(info)
+	if err != nil {
+		return err
+	}
+	fk, ok := elemInfo.fields[a.foreignKey]
+	if !ok {
+		return &NoPlaceToSaveFieldError{a.foreignKey}
+	}
+	models, err := a.find(ctx, db, a.foreignKey, keysOf(info, pk, owners))
+	if err != nil {
+		return err
+	}
+	groups := make(map[string][]interface{})
+	for _, m := range models {
+		k := keyOf(elemInfo.valueOf(m, fk).Interface())
+		groups[k] = append(groups[k], m)
+	}
+	for _, owner := range owners {
+		a.setMany(info, owner, groups[keyOf(info.valueOf(owner, pk).Interface())])
+	}
+	return nil
+}
+
+func (a *_Assoc) loadBelongsTo(ctx context.Context, db *DB, info, elemInfo *_StructInfo, owners []interface{}) error {
+	pk, err := singlePrimaryKey(elemInfo)
+	if err != nil {
+		return err
+	}
+	fk, ok := info.fields[a.foreignKey]
+	if !ok {
+		return &NoPlaceToSaveFieldError{a.foreignKey}
+	}
+	models, err := a.find(ctx, db, pk.name, keysOf(info, fk, owners))
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]interface{}, len(models))
+	for _, m := range models {
+		byKey[keyOf(elemInfo.valueOf(m, pk).Interface())] = m
+	}
+	for _, owner := range owners {
+		m, ok := byKey[keyOf(info.valueOf(owner, fk).Interface())]
+		if !ok {
+			continue
+		}
+		v := reflect.ValueOf(m)
+		if a.field._type.Kind() != reflect.Ptr {
+			v = v.Elem()
+		}
+		info.valueOf(owner, a.field).Set(v)
+	}
+	return nil
+}
+
+// ownerKeyAlias is the alias of the join table column referencing owners.
+const ownerKeyAlias = `taorm_owner_key`
+
+func (a *_Assoc) loadMany2Many(ctx context.Context, db *DB, info, elemInfo *_StructInfo, owners []interface{}) error {
+	pk, err := singlePrimaryKey(info)
+	if err != nil {
+		return err
+	}
+	elemPK, err := singlePrimaryKey(elemInfo)
+	if err != nil {
+		return err
+	}
+	keys := keysOf(info, pk, owners)
+	groups := make(map[string][]interface{})
+	size := db.dialect.MaxPlaceholders()
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := a.findMany2Many(ctx, db, elemInfo, elemPK, keys[start:end], groups); err != nil {
+			return err
+		}
+	}
+	for _, owner := range owners {
+		a.setMany(info, owner, groups[keyOf(info.valueOf(owner, pk).Interface())])
+	}
+	return nil
+}
+
+// findMany2Many finds associated structs through the join table,
+// and groups them by keys of owners.
+func (a *_Assoc) findMany2Many(ctx context.Context, db *DB, elemInfo *_StructInfo, elemPK _FieldInfo, keys []interface{}, groups map[string][]interface{}) error {
+	d := db.dialect
+	elem := reflect.New(a.elem).Interface()
+	s := db.From(elem)
+	table := s.table()
+	join := quoteIdent(d, a.joinTable)
+	joinKey := join + "." + quoteIdent(d, a.joinKey)
+
+	s.InnerJoin(join, join+"."+quoteIdent(d, a.joinRefKey)+"="+table+"."+quoteIdent(d, elemPK.name)).
+		Where(joinKey+" IN (?)", keys).
+		Select(table + ".*," + joinKey + " AS " + ownerKeyAlias)
+
+	rows, err := s.rows(ctx, elem)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	n := len(rows.columns)
+	if n == 0 || rows.columns[n-1] != ownerKeyAlias {
+		return ErrInvalidOut
+	}
+	layout, err := elemInfo.layoutOf(rows.columns[:n-1], db.scanMode)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		m := reflect.New(a.elem).Interface()
+		var key interface{}
+		ptrs := append(elemInfo.addrsOf(m, layout), &key)
+		if err := elemInfo.scanRow(rows.rows, m, layout, ptrs); err != nil {
+			return err
+		}
+		if err := elemInfo.callHook(_AfterFind, db, m); err != nil {
+			return err
+		}
+		k := keyOf(key)
+		groups[k] = append(groups[k], m)
+	}
+	if err := rows.rows.Err(); err != nil {
+		return err
+	}
+	return rows.rows.Close()
+}
@@ -0,0 +1,242 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Author struct {
+	ID    int64
+	Name  string
+	Books []*Book `taorm:"hasMany:author_id"`
+}
+
+func (Author) TableName() string {
+	return `authors`
+}
+
+type Book struct {
+	ID       int64
+	AuthorID int64
+	Title    string
+	Author   *Author `taorm:"belongsTo:author_id"`
+	Genres   []Genre `taorm:"many2many:book_genres"`
+}
+
+func (Book) TableName() string {
+	return `books`
+}
+
+type Genre struct {
+	ID   int64
+	Name string
+}
+
+func (Genre) TableName() string {
+	return `genres`
+}
+
+type Playlist struct {
+	ID    int64
+	Songs []*Song `taorm:"many2many:playlist_songs"`
+}
+
+func (Playlist) TableName() string {
+	return `playlists`
+}
+
+type Song struct {
+	ID      int64
+	Artists []string `taorm:"serializer:json"`
+	Album   *Album   `taorm:"nested:albums"`
+}
+
+func (Song) TableName() string {
+	return `songs`
+}
+
+type Album struct {
+	ID   int64
+	Name string
+}
+
+func (Album) TableName() string {
+	return `albums`
+}
+
+func TestPreload(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	mimic.SetRowsFunc(func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM authors`):
+			return []string{"id", "name"}, [][]driver.Value{{int64(1), "tao"}, {int64(2), "yang"}}
+		case strings.HasPrefix(query, `SELECT genres.*`):
+			return []string{"id", "name", ownerKeyAlias}, [][]driver.Value{
+				{int64(1), "go", []byte("10")},
+				{int64(2), "db", []byte("10")},
+				{int64(1), "go", []byte("11")},
+			}
+		default:
+			return []string{"id", "author_id", "title"}, [][]driver.Value{
+				{int64(10), int64(1), "a"},
+				{int64(11), int64(1), "b"},
+				{int64(12), int64(3), "c"},
+			}
+		}
+	})
+
+	var authors []Author
+	assert.NoError(t, tdb.From(Author{}).Preload(`Books`).Find(&authors))
+	assert.Len(t, authors, 2)
+	assert.Len(t, authors[0].Books, 2)
+	assert.Equal(t, `b`, authors[0].Books[1].Title)
+	assert.NotNil(t, authors[1].Books)
+	assert.Empty(t, authors[1].Books)
+
+	var books []*Book
+	assert.NoError(t, tdb.From(Book{}).Preload(`Author`, `Genres`).Find(&books))
+	assert.Len(t, books, 3)
+	assert.Equal(t, `tao`, books[0].Author.Name)
+	assert.Equal(t, `tao`, books[1].Author.Name)
+	assert.Nil(t, books[2].Author)
+	assert.Equal(t, []Genre{{1, `go`}, {2, `db`}}, books[0].Genres)
+	assert.Equal(t, []Genre{{1, `go`}}, books[1].Genres)
+	assert.Empty(t, books[2].Genres)
+
+	assert.Equal(t, []string{
+		`SELECT * FROM authors`,
+		`SELECT * FROM books WHERE (author_id IN (?,?))`,
+		`SELECT * FROM books`,
+		`SELECT * FROM authors WHERE (id IN (?,?))`,
+		`SELECT genres.*,book_genres.book_id AS taorm_owner_key FROM genres INNER JOIN book_genres ON book_genres.genre_id=genres.id WHERE (book_genres.book_id IN (?,?,?))`,
+	}, *queries)
+
+	err := tdb.From(Book{}).Preload(`Unknown`).Find(&books)
+	assert.Error(t, err)
+}
+
+// _SmallDialect is MySQL that allows only 2 bind variables in a statement.
+type _SmallDialect struct {
+	Dialect
+}
+
+func (_SmallDialect) MaxPlaceholders() int {
+	return 2
+}
+
+func TestPreloadChunks(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDBWithDialect(db, _SmallDialect{MySQL})
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	mimic.SetRowsFunc(func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM books`):
+			return []string{"id", "author_id", "title"}, [][]driver.Value{
+				{int64(10), int64(1), "a"},
+				{int64(11), int64(2), "b"},
+				{int64(12), int64(3), "c"},
+			}
+		case strings.HasSuffix(query, `IN (?))`):
+			return []string{"id", "name", ownerKeyAlias}, [][]driver.Value{{int64(1), "go", []byte("12")}}
+		default:
+			return []string{"id", "name", ownerKeyAlias}, [][]driver.Value{{int64(2), "db", []byte("10")}}
+		}
+	})
+
+	var books []*Book
+	assert.NoError(t, tdb.From(Book{}).Preload(`Genres`).Find(&books))
+	assert.Len(t, books, 3)
+	assert.Equal(t, []Genre{{2, `db`}}, books[0].Genres)
+	assert.Empty(t, books[1].Genres)
+	assert.Equal(t, []Genre{{1, `go`}}, books[2].Genres)
+	assert.Equal(t, []string{
+		`SELECT * FROM books`,
+		`SELECT genres.*,book_genres.book_id AS taorm_owner_key FROM genres INNER JOIN book_genres ON book_genres.genre_id=genres.id WHERE (book_genres.book_id IN (?,?))`,
+		`SELECT genres.*,book_genres.book_id AS taorm_owner_key FROM genres INNER JOIN book_genres ON book_genres.genre_id=genres.id WHERE (book_genres.book_id IN (?))`,
+	}, *queries)
+
+	*queries = nil
+	mimic.SetRowsFunc(func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM authors WHERE`):
+			return []string{"id", "name"}, [][]driver.Value{{int64(3), "tao"}}
+		default:
+			return []string{"id", "author_id", "title"}, [][]driver.Value{
+				{int64(10), int64(1), "a"},
+				{int64(11), int64(2), "b"},
+				{int64(12), int64(3), "c"},
+			}
+		}
+	})
+	assert.NoError(t, tdb.From(Book{}).Preload(`Author`).Find(&books))
+	assert.Nil(t, books[0].Author)
+	assert.Equal(t, `tao`, books[2].Author.Name)
+	assert.Equal(t, []string{
+		`SELECT * FROM books`,
+		`SELECT * FROM authors WHERE (id IN (?,?))`,
+		`SELECT * FROM authors WHERE (id IN (?))`,
+	}, *queries)
+}
+
+func TestPreloadOthers(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	mimic.SetRowsFunc(func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM authors`):
+			return []string{"id", "name"}, [][]driver.Value{{int64(1), "tao"}}
+		default:
+			return []string{"id", "author_id", "title"}, [][]driver.Value{{int64(10), int64(1), "a"}}
+		}
+	})
+
+	author, err := First[Author](tdb.From(Author{}).Preload(`Books`))
+	assert.NoError(t, err)
+	assert.Len(t, author.Books, 1)
+
+	_, err = tdb.From(Author{}).Preload(`Books`).Rows()
+	assert.Error(t, err)
+	err = Each(tdb.From(Author{}).Preload(`Books`), func(*Author) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestPreloadMany2ManyScan(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	mimic.SetRowsFunc(func(query string) ([]string, [][]driver.Value) {
+		switch {
+		case strings.HasPrefix(query, `SELECT * FROM playlists`):
+			return []string{"id"}, [][]driver.Value{{int64(1)}}
+		default:
+			return []string{"id", "artists", "albums__id", "albums__name", ownerKeyAlias}, [][]driver.Value{
+				{int64(10), []byte(`["a","b"]`), int64(100), "x", []byte("1")},
+				{int64(11), nil, nil, nil, []byte("1")},
+			}
+		}
+	})
+
+	var playlists []Playlist
+	assert.NoError(t, tdb.From(Playlist{}).Preload(`Songs`).Find(&playlists))
+	assert.Equal(t, []*Song{
+		{ID: 10, Artists: []string{`a`, `b`}, Album: &Album{ID: 100, Name: `x`}},
+		{ID: 11},
+	}, playlists[0].Songs)
+}
@@ -1,6 +1,7 @@
 package taorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
@@ -10,8 +11,14 @@ import (
 
 // _FieldInfo stores info about a field in a struct.
 type _FieldInfo struct {
-	offset uintptr      // the memory offset of the field
-	_type  reflect.Type // the reflection type of the field
+	offset     uintptr      // the memory offset of the field
+	_type      reflect.Type // the reflection type of the field
+	name       string       // the column name of the field
+	pk         bool         // tagged as primary key
+	nested     *_Nested     // the pointer nested struct the column belongs to, if any
+	discard    bool         // the column has no field and is discarded
+	ptrValuer  bool         // driver.Valuer is implemented by pointer receiver
+	serializer Serializer   // the serializer of the column, if tagged with `serializer`
 }
 
 // StructInfo stores info about a struct.
@@ -23,12 +30,22 @@ type _StructInfo struct {
 	insertIdStr  string                // for insert with id
 	updatestr    string                // for update
 	insertFields []_FieldInfo          // offsets of member to insert
-	pkeyField    _FieldInfo
+	pkeyFields   []_FieldInfo          // fields of the primary key, more than one if composite
+	insertNames  []string              // column names of insertFields
+	needsQuote   bool                  // if any of the names needs quoting
+	hooks        _Hooks                // hooks implemented by the struct
+	autoTimes    []_AutoTime           // columns set to the current time automatically
+	softDelete   *_FieldInfo           // the soft delete column, if any
+	version      *_FieldInfo           // the version column for optimistic locking, if any
+	assocs       map[string]*_Assoc    // associations by field names
+	nested       []*_Nested            // nested structs of joined tables
+	hasNestedPtr bool                  // if any of nested is a pointer
 }
 
 func newStructInfo() *_StructInfo {
 	return &_StructInfo{
 		fields: make(map[string]_FieldInfo),
+		assocs: make(map[string]*_Assoc),
 	}
 }
 
@@ -46,47 +63,155 @@ func (s *_StructInfo) addrOf(out interface{}, field _FieldInfo) interface{} {
 	).Interface()
 }
 
-func (s *_StructInfo) ptrsOf(out interface{}, fields []string) ([]interface{}, error) {
-	ptrs := make([]interface{}, 0, len(fields))
-	for _, field := range fields {
-		fi, ok := s.fields[field]
+// layoutOf returns the fields to scan columns into.
+// Columns without fields are handled by mode.
+func (s *_StructInfo) layoutOf(columns []string, mode ScanMode) ([]_FieldInfo, error) {
+	layout := make([]_FieldInfo, 0, len(columns))
+	var missing []string
+	for _, column := range columns {
+		fi, ok := s.fields[column]
 		if !ok {
-			return nil, &NoPlaceToSaveFieldError{field}
+			switch mode {
+			case ScanLenient:
+				layout = append(layout, _FieldInfo{name: column, discard: true})
+			case ScanStrict:
+				missing = append(missing, column)
+			default:
+				return nil, &NoPlaceToSaveFieldError{column}
+			}
+			continue
 		}
-		addr := s.addrOf(out, fi)
-		ptrs = append(ptrs, addr)
+		layout = append(layout, fi)
+	}
+	if len(missing) > 0 {
+		return nil, &NoPlaceToSaveFieldsError{missing}
 	}
-	return ptrs, nil
+	return layout, nil
+}
+
+// addrsOf returns addresses of fields of out to scan into.
+//
+// Fields of pointer nested structs are scanned into temporary pointers,
+// which are set to out by scanRow.
+func (s *_StructInfo) addrsOf(out interface{}, fields []_FieldInfo) []interface{} {
+	ptrs := make([]interface{}, 0, len(fields))
+	for _, fi := range fields {
+		if fi.discard {
+			ptrs = append(ptrs, discard)
+			continue
+		}
+		if fi.nested != nil {
+			ptr := reflect.New(reflect.PtrTo(fi._type))
+			if fi.serializer != nil {
+				ptrs = append(ptrs, _Deserializer{serializer: fi.serializer, ptr: ptr, indirect: true})
+				continue
+			}
+			ptrs = append(ptrs, ptr.Interface())
+			continue
+		}
+		if fi.serializer != nil {
+			ptrs = append(ptrs, _Deserializer{serializer: fi.serializer, ptr: s.valueOf(out, fi).Addr()})
+			continue
+		}
+		ptrs = append(ptrs, s.addrOf(out, fi))
+	}
+	return ptrs
+}
+
+// scanRow scans the current row into out by ptrs returned by addrsOf(out, layout).
+func (s *_StructInfo) scanRow(rows *sql.Rows, out interface{}, layout []_FieldInfo, ptrs []interface{}) error {
+	if err := rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	if s.hasNestedPtr {
+		s.setNested(out, layout, ptrs)
+	}
+	return nil
 }
 
 // TODO works also with pk
 func (s *_StructInfo) ifacesOf(out interface{}) []interface{} {
-	values := make([]interface{}, len(s.insertFields))
-	for i, f := range s.insertFields {
-		values[i] = reflect.NewAt(
+	return s.valuesOf(out, s.insertFields)
+}
+
+// valuesOf returns values of fields of out.
+func (s *_StructInfo) valuesOf(out interface{}, fields []_FieldInfo) []interface{} {
+	values := make([]interface{}, len(fields))
+	for i, f := range fields {
+		value := reflect.NewAt(
 			f._type,
 			unsafe.Pointer(uintptr((*_EmptyEface)(unsafe.Pointer(&out)).ptr)+f.offset),
-		).Elem().Interface()
+		)
+		if f.serializer != nil {
+			values[i] = _Serialized{serializer: f.serializer, value: value.Elem().Interface()}
+			continue
+		}
+		if f.ptrValuer {
+			values[i] = value.Interface()
+			continue
+		}
+		values[i] = value.Elem().Interface()
 	}
 	return values
 }
 
+// setPrimaryKey sets the auto-generated primary key.
 func (s *_StructInfo) setPrimaryKey(out interface{}, id int64) {
-	pkey := s.valueOf(out, s.pkeyField)
-	switch s.pkeyField._type.Kind() {
-	case reflect.Uint, reflect.Uint64:
+	pkeyField := s.pkeyFields[0]
+	pkey := s.valueOf(out, pkeyField)
+	switch pkeyField._type.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		pkey.SetUint(uint64(id))
-	case reflect.Int, reflect.Int64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		pkey.SetInt(id)
 	default:
 		panic("cannot set primary key")
 	}
 }
 
-func (s *_StructInfo) getPrimaryKey(out interface{}) (interface{}, bool) {
-	zero := reflect.Zero(s.pkeyField._type).Interface()
-	pkv := s.valueOf(out, s.pkeyField).Interface()
-	return pkv, pkv != zero
+// hasSinglePrimaryKey reports whether the primary key is a single column,
+// which can be auto-generated.
+func (s *_StructInfo) hasSinglePrimaryKey() bool {
+	return len(s.pkeyFields) == 1
+}
+
+// hasIntPrimaryKey reports whether the primary key can be set by setPrimaryKey.
+func (s *_StructInfo) hasIntPrimaryKey() bool {
+	if !s.hasSinglePrimaryKey() {
+		return false
+	}
+	switch s.pkeyFields[0]._type.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// getPrimaryKey gets values of the primary key.
+// The key is set only if all its values are non-zero.
+func (s *_StructInfo) getPrimaryKey(out interface{}) ([]interface{}, bool) {
+	if len(s.pkeyFields) == 0 {
+		return nil, false
+	}
+	values := make([]interface{}, 0, len(s.pkeyFields))
+	set := true
+	for _, f := range s.pkeyFields {
+		zero := reflect.Zero(f._type).Interface()
+		pkv := s.valueOf(out, f).Interface()
+		values = append(values, pkv)
+		set = set && pkv != zero
+	}
+	return values, set
+}
+
+// pkeyNames returns the column names of the primary key.
+func (s *_StructInfo) pkeyNames() []string {
+	names := make([]string, 0, len(s.pkeyFields))
+	for _, f := range s.pkeyFields {
+		names = append(names, f.name)
+	}
+	return names
 }
 
 // structs maps struct type name to its info.
@@ -113,66 +238,163 @@ func register(ty reflect.Type) (*_StructInfo, error) {
 
 	structInfo := newStructInfo()
 	structInfo.tableName = tableName
-	fieldNames := []string{}
-
-	addStructFields(structInfo, ty, &fieldNames)
+	structInfo.hooks = hooksOf(ty)
+	columns := []_FieldInfo{}
 
-	structInfo.fieldstr = strings.Join(fieldNames, ",")
-	{
-		query := fmt.Sprintf(`INSERT INTO %s `, tableName)
-		query += fmt.Sprintf(`(%s) VALUES (%s)`,
-			structInfo.fieldstr,
-			createSQLInMarks(len(fieldNames)),
-		)
-		structInfo.insertstr = query
-	}
-	{
-		query := fmt.Sprintf(`INSERT INTO %s `, tableName)
-		query += fmt.Sprintf(`(id,%s) VALUES (%s)`,
-			structInfo.fieldstr,
-			createSQLInMarks(1+len(fieldNames)),
-		)
-		structInfo.insertIdStr = query
+	if err := addStructFields(structInfo, ty, &columns); err != nil {
+		return nil, err
 	}
-	{
-		query := fmt.Sprintf(`UPDATE %s SET `, tableName)
-		pairs := []string{}
-		for _, name := range fieldNames {
-			pairs = append(pairs, name+"=?")
-		}
-		query += strings.Join(pairs, ",")
-		structInfo.updatestr = query
+	structInfo.addColumns(columns)
+
+	structInfo.needsQuote = needsQuote(tableName)
+	for _, name := range append(structInfo.pkeyNames(), structInfo.insertNames...) {
+		structInfo.needsQuote = structInfo.needsQuote || needsQuote(name)
 	}
+
+	structInfo.fieldstr = strings.Join(structInfo.insertNames, ",")
+	structInfo.insertstr = structInfo.buildInsertStr(nil, false)
+	structInfo.insertIdStr = structInfo.buildInsertStr(nil, true)
+	structInfo.updatestr = structInfo.buildUpdateStr(nil)
 	structs[typeName] = structInfo
 	//fmt.Printf("taorm: registered: %s\n", typeName)
 	return structInfo, nil
 }
 
-func addStructFields(info *_StructInfo, ty reflect.Type, fieldNames *[]string) {
+// buildInsertStr builds the INSERT statement.
+// Names are quoted by d, or left as is if d is nil.
+func (s *_StructInfo) buildInsertStr(d Dialect, withID bool) string {
+	names := make([]string, 0, len(s.insertNames)+1)
+	if withID {
+		for _, name := range s.pkeyNames() {
+			names = append(names, s.quote(d, name))
+		}
+	}
+	for _, name := range s.insertNames {
+		names = append(names, s.quote(d, name))
+	}
+	query := fmt.Sprintf(`INSERT INTO %s `, s.quote(d, s.tableName))
+	query += fmt.Sprintf(`(%s) VALUES (%s)`,
+		strings.Join(names, ","),
+		createSQLInMarks(len(names)),
+	)
+	return query
+}
+
+// buildUpdateStr builds the UPDATE statement without wheres.
+// Names are quoted by d, or left as is if d is nil.
+func (s *_StructInfo) buildUpdateStr(d Dialect) string {
+	return s.buildUpdateStrOf(d, s.insertFields)
+}
+
+// buildUpdateStrOf builds the UPDATE statement of fields without wheres.
+func (s *_StructInfo) buildUpdateStrOf(d Dialect, fields []_FieldInfo) string {
+	query := fmt.Sprintf(`UPDATE %s SET `, s.quote(d, s.tableName))
+	pairs := []string{}
+	for _, f := range fields {
+		name := f.name
+		if s.version != nil && name == s.version.name {
+			pairs = append(pairs, s.quote(d, name)+"="+s.quote(d, name)+"+1")
+			continue
+		}
+		pairs = append(pairs, s.quote(d, name)+"=?")
+	}
+	query += strings.Join(pairs, ",")
+	return query
+}
+
+// insertStrOf returns the INSERT statement for dialect d.
+func (s *_StructInfo) insertStrOf(d Dialect, withID bool) string {
+	if s.needsQuote {
+		return s.buildInsertStr(d, withID)
+	}
+	if withID {
+		return s.insertIdStr
+	}
+	return s.insertstr
+}
+
+// updateStrOf returns the UPDATE statement for dialect d.
+func (s *_StructInfo) updateStrOf(d Dialect) string {
+	if s.needsQuote {
+		return s.buildUpdateStr(d)
+	}
+	return s.updatestr
+}
+
+func (s *_StructInfo) quote(d Dialect, name string) string {
+	if d == nil {
+		return name
+	}
+	return quoteIdent(d, name)
+}
+
+// addColumns sets the primary key and fields to insert.
+//
+// The primary key is made up of the columns tagged with `pk`, or the column
+// named `id` if there is no such tag.
+func (s *_StructInfo) addColumns(columns []_FieldInfo) {
+	tagged := false
+	for _, c := range columns {
+		tagged = tagged || c.pk
+	}
+	for _, c := range columns {
+		if c.pk || !tagged && c.name == "id" {
+			s.pkeyFields = append(s.pkeyFields, c)
+		} else {
+			s.insertFields = append(s.insertFields, c)
+			s.insertNames = append(s.insertNames, c.name)
+		}
+	}
+}
+
+func addStructFields(info *_StructInfo, ty reflect.Type, columns *[]_FieldInfo) error {
 	for i := 0; i < ty.NumField(); i++ {
 		f := ty.Field(i)
-		if isColumnField(f) {
+		serializer, err := getSerializer(f)
+		if err != nil {
+			return err
+		}
+		if serializer != nil || isColumnField(f) {
 			columnName := getColumnName(f)
 			if columnName == "" {
 				continue
 			}
-			if columnName != "id" {
-				*fieldNames = append(*fieldNames, columnName)
-			}
+			_, pk := getTagOption(f, "pk")
 			fieldInfo := _FieldInfo{
-				offset: f.Offset,
-				_type:  f.Type,
+				offset:     f.Offset,
+				_type:      f.Type,
+				name:       columnName,
+				pk:         pk,
+				ptrValuer:  !f.Type.Implements(valuerType) && reflect.PtrTo(f.Type).Implements(valuerType),
+				serializer: serializer,
 			}
 			info.fields[columnName] = fieldInfo
-			if columnName != "id" {
-				info.insertFields = append(info.insertFields, fieldInfo)
-			} else {
-				info.pkeyField = fieldInfo
+			*columns = append(*columns, fieldInfo)
+			if err := info.addAutoTime(f, fieldInfo); err != nil {
+				return err
+			}
+			if err := info.addSoftDelete(f, fieldInfo); err != nil {
+				return err
+			}
+			if err := info.addVersion(f, fieldInfo); err != nil {
+				return err
+			}
+		} else if assoc, err := getAssoc(ty, f); err != nil || assoc != nil {
+			if err != nil {
+				return err
+			}
+			info.assocs[f.Name] = assoc
+		} else if prefix, ok := getTagOption(f, "nested"); ok {
+			if err := info.addNested(f, prefix); err != nil {
+				return err
 			}
 		} else if f.Anonymous {
-			addStructFields(info, f.Type, fieldNames)
+			if err := addStructFields(info, f.Type, columns); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
 // _struct can be any struct-related types.
@@ -0,0 +1,138 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+)
+
+// Rows iterates over rows of a statement one at a time.
+//
+// Rows must be closed, which is safe to do more than once.
+type Rows struct {
+	db      *DB
+	rows    *sql.Rows
+	columns []string
+	info    *_StructInfo // struct of the last scan
+	layout  []_FieldInfo // fields of info to scan columns into
+}
+
+// errPreloadRows is returned if Rows or Each is called with Preload.
+var errPreloadRows = errors.New("taorm: Preload is not supported by Rows and Each")
+
+// Rows executes the statement and returns an iterator over the result rows.
+func (s *Stmt) Rows() (*Rows, error) {
+	return s.RowsContext(context.Background())
+}
+
+// RowsContext ...
+func (s *Stmt) RowsContext(ctx context.Context) (*Rows, error) {
+	rows, err := s.rows(ctx, s.model)
+	return rows, WrapError(err)
+}
+
+func (s *Stmt) rows(ctx context.Context, out interface{}) (*Rows, error) {
+	if len(s.preloads) > 0 {
+		return nil, errPreloadRows
+	}
+	query, args, err := s.buildSelect(out, false)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.query(ctx, OpFind, s.table(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return &Rows{
+		db:      s.db,
+		rows:    rows,
+		columns: columns,
+	}, nil
+}
+
+// Next prepares the next row for Scan, and returns false if there are no
+// more rows or an error occurred, which is reported by Err.
+func (r *Rows) Next() bool {
+	return r.rows.Next()
+}
+
+// Scan scans the current row into out, which is either *Struct or *primitive.
+//
+// The fields to scan into are looked up once for the same struct type.
+func (r *Rows) Scan(out interface{}) error {
+	ty := reflect.TypeOf(out)
+	if ty == nil || ty.Kind() != reflect.Ptr {
+		return WrapError(ErrInvalidOut)
+	}
+	if !isModelType(ty.Elem()) {
+		if len(r.columns) != 1 {
+			return WrapError(ErrInvalidOut)
+		}
+		return WrapError(r.rows.Scan(out))
+	}
+	info, err := getRegistered(out)
+	if err != nil {
+		return WrapError(err)
+	}
+	if info != r.info {
+		layout, err := info.layoutOf(r.columns, r.db.scanMode)
+		if err != nil {
+			return WrapError(err)
+		}
+		r.info, r.layout = info, layout
+	}
+	if err := info.scanRow(r.rows, out, r.layout, info.addrsOf(out, r.layout)); err != nil {
+		return WrapError(err)
+	}
+	return WrapError(info.callHook(_AfterFind, r.db, out))
+}
+
+// Err returns the error occurred during iteration.
+func (r *Rows) Err() error {
+	return WrapError(r.rows.Err())
+}
+
+// Close closes the rows.
+func (r *Rows) Close() error {
+	return WrapError(r.rows.Close())
+}
+
+// Each calls fn for each row of s scanned as T, which is a struct, without
+// loading all rows into memory.
+//
+// The same *T is reused for all rows, so it must not be retained by fn.
+// Iteration stops at the first error returned by fn, which is returned as is.
+func Each[T any](s *Stmt, fn func(*T) error) error {
+	return EachContext(context.Background(), s, fn)
+}
+
+// EachContext ...
+func EachContext[T any](ctx context.Context, s *Stmt, fn func(*T) error) error {
+	elem := new(T)
+	rows, err := s.rows(ctx, elem)
+	if err != nil {
+		return WrapError(err)
+	}
+	defer rows.Close()
+
+	var zero T
+	for rows.Next() {
+		*elem = zero
+		if err := rows.Scan(elem); err != nil {
+			return err
+		}
+		if err := fn(elem); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return rows.Close()
+}
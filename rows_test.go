@@ -0,0 +1,56 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRows(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	mimic.SetRows([]string{"id", "name", "age"}, [][]driver.Value{
+		{int64(1), "tao", 18},
+		{int64(2), "yang", 20},
+		{int64(3), "li", 22},
+	})
+
+	rows, err := tdb.From(User{}).Rows()
+	assert.NoError(t, err)
+	var users []User
+	for rows.Next() {
+		var u User
+		assert.NoError(t, rows.Scan(&u))
+		users = append(users, u)
+	}
+	assert.NoError(t, rows.Err())
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, []User{{1, `tao`, 18}, {2, `yang`, 20}, {3, `li`, 22}}, users)
+
+	names := []string{}
+	assert.NoError(t, Each(tdb.Where(`age>?`, 1), func(u *User) error {
+		names = append(names, u.Name)
+		return nil
+	}))
+	assert.Equal(t, []string{`tao`, `yang`, `li`}, names)
+
+	errStop := errors.New(`stop`)
+	n := 0
+	err = Each(tdb.From(User{}), func(u *User) error {
+		n++
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 0, db.Stats().InUse)
+
+	mimic.SetRows([]string{"id", "unknown"}, [][]driver.Value{{int64(1), "x"}})
+	err = Each(tdb.From(User{}), func(u *User) error { return nil })
+	assert.Error(t, err)
+	assert.Equal(t, 0, db.Stats().InUse)
+}
@@ -1,6 +1,7 @@
 package taorm
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 	"unsafe"
@@ -8,14 +9,42 @@ import (
 
 // ScanRows scans result rows into out.
 //
-// out can be either *primitive, *Struct, *[]Struct, or *[]*Struct.
-func ScanRows(out interface{}, tx _SQLCommon, query string, args ...interface{}) (_err error) {
-	defer func() { _err = WrapError(_err) }()
+// out can be either *primitive, *Struct, *[]primitive, *[]Struct, *[]*Struct,
+// *map[string]any, *[]map[string]any, or *map[K]V.
+//
+// *[]primitive requires a single column, and *map[K]V requires two columns
+// of keys and values. Values of []byte in map[string]any are converted to string.
+// AfterFind of structs is called with tx if tx is a *DB, or nil otherwise.
+func ScanRows(out interface{}, tx _SQLCommon, query string, args ...interface{}) error {
+	return ScanRowsContext(context.Background(), out, tx, query, args...)
+}
 
-	rows, err := tx.Query(query, args...)
+// ScanRowsContext is like ScanRows but the query is bound to ctx.
+func ScanRowsContext(ctx context.Context, out interface{}, tx _SQLCommon, query string, args ...interface{}) error {
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return WrapError(err)
 	}
+	db, _ := tx.(*DB)
+	return scanRows(db, out, rows)
+}
+
+// isModelType returns true if ty is a struct that is scanned by fields.
+func isModelType(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Struct && !isColumnType(ty)
+}
+
+// isRowMapType returns true if ty is like map[string]any, which holds a row.
+func isRowMapType(ty reflect.Type) bool {
+	return ty.Kind() == reflect.Map &&
+		ty.Key().Kind() == reflect.String &&
+		ty.Elem().Kind() == reflect.Interface && ty.Elem().NumMethod() == 0
+}
+
+// scanRows scans rows into out and closes rows.
+// db is passed to AfterFind hooks.
+func scanRows(db *DB, out interface{}, rows *sql.Rows) (_err error) {
+	defer func() { _err = WrapError(_err) }()
 
 	defer rows.Close()
 
@@ -30,60 +59,58 @@ func ScanRows(out interface{}, tx _SQLCommon, query string, args ...interface{})
 	}
 
 	ty = ty.Elem()
-	switch ty.Kind() {
-	case reflect.Struct:
+	switch {
+	case isModelType(ty):
 		info, err := getRegistered(out)
 		if err != nil {
 			return err
 		}
 		if rows.Next() {
-			pointers, err := info.ptrsOf(out, columns)
+			layout, err := info.layoutOf(columns, scanModeOf(db))
 			if err != nil {
 				return err
 			}
-			return rows.Scan(pointers...)
+			if err := info.scanRow(rows, out, layout, info.addrsOf(out, layout)); err != nil {
+				return err
+			}
+			return info.callHook(_AfterFind, db, out)
 		}
-		err = rows.Err()
-		if err == nil {
-			err = sql.ErrNoRows
+		return noRows(rows)
+	case isRowMapType(ty):
+		if rows.Next() {
+			m, err := scanRowMap(rows, columns, ty)
+			if err != nil {
+				return err
+			}
+			reflect.ValueOf(out).Elem().Set(m)
+			return nil
 		}
-		return err
-	case reflect.Slice:
+		return noRows(rows)
+	case ty.Kind() == reflect.Map:
+		return scanKeyValues(out, rows, columns)
+	case ty.Kind() == reflect.Slice && ty.Elem().Kind() != reflect.Uint8:
 		slice := reflect.MakeSlice(ty, 0, 0)
 		ty = ty.Elem()
-		isPtr := ty.Kind() == reflect.Ptr
-		if isPtr {
-			ty = ty.Elem()
-		}
-		if ty.Kind() != reflect.Struct {
-			return ErrInvalidOut
-		}
-		info, err := getRegistered(reflect.NewAt(ty, unsafe.Pointer(nil)).Interface())
-		if err != nil {
-			return err
-		}
-		if isPtr {
+		switch {
+		case isRowMapType(ty):
 			for rows.Next() {
-				elem := reflect.New(ty)
-				elemPtr := elem.Interface()
-				pointers, err := info.ptrsOf(elemPtr, columns)
+				m, err := scanRowMap(rows, columns, ty)
 				if err != nil {
 					return err
 				}
-				if err := rows.Scan(pointers...); err != nil {
-					return err
-				}
-				slice = reflect.Append(slice, elem)
+				slice = reflect.Append(slice, m)
 			}
-		} else {
-			elem := reflect.New(ty)
-			elemPtr := elem.Interface()
-			pointers, err := info.ptrsOf(elemPtr, columns)
-			if err != nil {
+		case isModelType(ty) || ty.Kind() == reflect.Ptr && isModelType(ty.Elem()):
+			if err := scanModels(db, &slice, rows, columns); err != nil {
 				return err
 			}
+		default:
+			if len(columns) != 1 {
+				return ErrInvalidOut
+			}
 			for rows.Next() {
-				if err := rows.Scan(pointers...); err != nil {
+				elem := reflect.New(ty)
+				if err := rows.Scan(elem.Interface()); err != nil {
 					return err
 				}
 				slice = reflect.Append(slice, elem.Elem())
@@ -98,12 +125,105 @@ func ScanRows(out interface{}, tx _SQLCommon, query string, args ...interface{})
 		if rows.Next() {
 			return rows.Scan(out)
 		}
-		err = rows.Err()
-		if err == nil {
-			err = sql.ErrNoRows
-		}
+		return noRows(rows)
+	}
+}
+
+// noRows returns the error of rows, or sql.ErrNoRows if no errors.
+func noRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
 		return err
 	}
+	return sql.ErrNoRows
+}
+
+// scanModels scans rows into slice of structs or pointers to structs.
+func scanModels(db *DB, slice *reflect.Value, rows *sql.Rows, columns []string) error {
+	ty := slice.Type().Elem()
+	isPtr := ty.Kind() == reflect.Ptr
+	if isPtr {
+		ty = ty.Elem()
+	}
+	info, err := getRegistered(reflect.NewAt(ty, unsafe.Pointer(nil)).Interface())
+	if err != nil {
+		return err
+	}
+	layout, err := info.layoutOf(columns, scanModeOf(db))
+	if err != nil {
+		return err
+	}
+	if isPtr {
+		for rows.Next() {
+			elem := reflect.New(ty)
+			elemPtr := elem.Interface()
+			if err := info.scanRow(rows, elemPtr, layout, info.addrsOf(elemPtr, layout)); err != nil {
+				return err
+			}
+			if err := info.callHook(_AfterFind, db, elemPtr); err != nil {
+				return err
+			}
+			*slice = reflect.Append(*slice, elem)
+		}
+	} else {
+		elem := reflect.New(ty)
+		elemPtr := elem.Interface()
+		pointers := info.addrsOf(elemPtr, layout)
+		for rows.Next() {
+			// the elem is reused, clear states left by hooks of the last row.
+			if info.hooks&_AfterFind != 0 {
+				elem.Elem().Set(reflect.Zero(ty))
+			}
+			if err := info.scanRow(rows, elemPtr, layout, pointers); err != nil {
+				return err
+			}
+			if err := info.callHook(_AfterFind, db, elemPtr); err != nil {
+				return err
+			}
+			*slice = reflect.Append(*slice, elem.Elem())
+		}
+	}
+	return nil
+}
+
+// scanRowMap scans the current row into a new map of type ty.
+func scanRowMap(rows *sql.Rows, columns []string, ty reflect.Type) (reflect.Value, error) {
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return reflect.Value{}, err
+	}
+	m := reflect.MakeMapWithSize(ty, len(columns))
+	for i, column := range columns {
+		value := values[i]
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		v := reflect.ValueOf(&value).Elem()
+		m.SetMapIndex(reflect.ValueOf(column).Convert(ty.Key()), v)
+	}
+	return m, nil
+}
+
+// scanKeyValues scans rows of keys and values into out of *map[K]V.
+func scanKeyValues(out interface{}, rows *sql.Rows, columns []string) error {
+	if len(columns) != 2 {
+		return ErrInvalidOut
+	}
+	ty := reflect.TypeOf(out).Elem()
+	m := reflect.MakeMap(ty)
+	for rows.Next() {
+		key := reflect.New(ty.Key())
+		value := reflect.New(ty.Elem())
+		if err := rows.Scan(key.Interface(), value.Interface()); err != nil {
+			return err
+		}
+		m.SetMapIndex(key.Elem(), value.Elem())
+	}
+	reflect.ValueOf(out).Elem().Set(m)
+	return rows.Err()
 }
 
 // MustScanRows ...
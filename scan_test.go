@@ -0,0 +1,59 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanRows(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	mimic.SetRows([]string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}})
+	var ids []int64
+	assert.NoError(t, tdb.From(User{}).Pluck(`id`, &ids))
+	assert.Equal(t, []int64{1, 2}, ids)
+
+	var names []string
+	assert.NoError(t, ScanRows(&names, tdb, `SELECT name FROM users`))
+	assert.Equal(t, []string{`1`, `2`}, names)
+
+	now := time.Now()
+	mimic.SetRows([]string{"created_at"}, [][]driver.Value{{now}})
+	var times []time.Time
+	assert.NoError(t, ScanRows(&times, tdb, `SELECT created_at FROM users`))
+	assert.Equal(t, []time.Time{now}, times)
+	var created time.Time
+	assert.NoError(t, ScanRows(&created, tdb, `SELECT created_at FROM users`))
+	assert.Equal(t, now, created)
+
+	mimic.SetRows([]string{"name", "count"}, [][]driver.Value{
+		{[]byte(`tao`), int64(2)},
+		{[]byte(`yang`), nil},
+	})
+	var row map[string]interface{}
+	assert.NoError(t, ScanRows(&row, tdb, `SELECT name,COUNT(1) AS count FROM users`))
+	assert.Equal(t, map[string]interface{}{`name`: `tao`, `count`: int64(2)}, row)
+
+	var report []M
+	assert.NoError(t, ScanRows(&report, tdb, `SELECT name,COUNT(1) AS count FROM users`))
+	assert.Equal(t, []M{
+		{`name`: `tao`, `count`: int64(2)},
+		{`name`: `yang`, `count`: nil},
+	}, report)
+
+	mimic.SetRows([]string{"name", "age"}, [][]driver.Value{{`tao`, int64(18)}, {`yang`, int64(20)}})
+	var ages map[string]int
+	assert.NoError(t, ScanRows(&ages, tdb, `SELECT name,age FROM users`))
+	assert.Equal(t, map[string]int{`tao`: 18, `yang`: 20}, ages)
+
+	assert.Equal(t, ErrInvalidOut, ScanRows(&ids, tdb, `SELECT name,age FROM users`).(*Error).Raw)
+
+	mimic.SetRows([]string{"id", "name", "age"}, nil)
+	assert.True(t, IsNotFoundError(ScanRows(&row, tdb, `SELECT * FROM users`)))
+}
@@ -0,0 +1,48 @@
+package taorm
+
+// ScanMode is how result columns without matching fields are handled
+// when scanning into structs.
+type ScanMode int
+
+// Scan modes.
+const (
+	// ScanDefault fails with a NoPlaceToSaveFieldError of the first unknown column.
+	ScanDefault ScanMode = iota
+	// ScanLenient discards unknown columns, e.g.: for `SELECT *` of tables
+	// with columns added before the structs.
+	ScanLenient
+	// ScanStrict fails with a NoPlaceToSaveFieldsError of all unknown columns.
+	ScanStrict
+)
+
+// WithScanMode returns a copy of db that scans in mode.
+//
+// Transactions started from the returned db scan in mode too.
+func (db *DB) WithScanMode(mode ScanMode) *DB {
+	c := *db
+	c.scanMode = mode
+	return &c
+}
+
+// WithScanMode makes the statement scan in mode.
+func (s *Stmt) WithScanMode(mode ScanMode) *Stmt {
+	s.db = s.db.WithScanMode(mode)
+	return s
+}
+
+// scanModeOf returns the scan mode of db, which can be nil.
+func scanModeOf(db *DB) ScanMode {
+	if db == nil {
+		return ScanDefault
+	}
+	return db.scanMode
+}
+
+// _Discard is a sql.Scanner that discards values.
+type _Discard struct{}
+
+func (_Discard) Scan(interface{}) error {
+	return nil
+}
+
+var discard = &_Discard{}
@@ -0,0 +1,44 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanMode(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	mimic.SetRows([]string{"id", "nickname", "name", "email", "age"}, [][]driver.Value{
+		{int64(1), "t", "tao", "t@example.com", 18},
+	})
+
+	var users []User
+	err := tdb.From(User{}).Find(&users)
+	assert.Equal(t, &NoPlaceToSaveFieldError{`nickname`}, err.(*Error).Raw)
+
+	err = tdb.From(User{}).WithScanMode(ScanStrict).Find(&users)
+	assert.Equal(t, &NoPlaceToSaveFieldsError{[]string{`nickname`, `email`}}, err.(*Error).Raw)
+
+	assert.NoError(t, tdb.From(User{}).WithScanMode(ScanLenient).Find(&users))
+	assert.Equal(t, []User{{1, `tao`, 18}}, users)
+
+	lenient := tdb.WithScanMode(ScanLenient)
+	var user User
+	assert.NoError(t, lenient.TxCall(func(tx *DB) error {
+		return tx.From(User{}).Find(&user)
+	}))
+	assert.Equal(t, User{1, `tao`, 18}, user)
+
+	rows, err := lenient.From(User{}).Rows()
+	assert.NoError(t, err)
+	defer rows.Close()
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.Scan(&user))
+
+	assert.Error(t, tdb.From(User{}).Find(&user))
+}
@@ -0,0 +1,167 @@
+package taorm
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"sync"
+)
+
+// Serializer marshals fields tagged with `serializer:name` to column values
+// and unmarshals them back.
+//
+// Such fields can be of any type, e.g. maps, slices and structs.
+type Serializer interface {
+	// Marshal returns the column value of v, usually a string for text
+	// formats and a []byte for binary formats.
+	Marshal(v interface{}) (driver.Value, error)
+	// Unmarshal parses data scanned from the column into v, which is a pointer.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type _JSONSerializer struct{}
+
+func (_JSONSerializer) Marshal(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (_JSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type _GobSerializer struct{}
+
+func (_GobSerializer) Marshal(v interface{}) (driver.Value, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (_GobSerializer) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// serializers maps names to registered serializers.
+var serializers = map[string]Serializer{
+	"json": _JSONSerializer{},
+	"gob":  _GobSerializer{},
+}
+var serializersLock = &sync.RWMutex{}
+
+// RegisterSerializer registers s as name to be used in `serializer:name`.
+//
+// It must be called before any model using it is registered, and replaces
+// the serializer of the same name, including the builtin json and gob.
+func RegisterSerializer(name string, s Serializer) {
+	serializersLock.Lock()
+	defer serializersLock.Unlock()
+	serializers[name] = s
+}
+
+// getSerializer gets the serializer of field tagged with `serializer:name`.
+// It returns nil if field is not tagged.
+func getSerializer(field reflect.StructField) (Serializer, error) {
+	name, ok := getTagOption(field, "serializer")
+	if !ok {
+		return nil, nil
+	}
+	if !ast.IsExported(field.Name) {
+		return nil, fmt.Errorf("taorm: serialized field %s must be exported", field.Name)
+	}
+	serializersLock.RLock()
+	defer serializersLock.RUnlock()
+	s, ok := serializers[name]
+	if !ok {
+		return nil, fmt.Errorf("taorm: unknown serializer %q of field %s", name, field.Name)
+	}
+	return s, nil
+}
+
+// _Serialized is the value of a serialized field.
+// Nil maps, slices and pointers are NULL.
+type _Serialized struct {
+	serializer Serializer
+	value      interface{}
+}
+
+func (s _Serialized) Value() (driver.Value, error) {
+	if s.value == nil {
+		return nil, nil
+	}
+	switch rv := reflect.ValueOf(s.value); rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+	return s.serializer.Marshal(s.value)
+}
+
+// withSerialized returns a copy of fields with values of serialized columns
+// wrapped in _Serialized. Values of type _Expr are left as is.
+func (s *_StructInfo) withSerialized(fields M) M {
+	copied := false
+	for name, value := range fields {
+		f, ok := s.fields[name]
+		if !ok || f.serializer == nil {
+			continue
+		}
+		switch value.(type) {
+		case _Expr, _Serialized:
+			continue
+		}
+		if !copied {
+			m := make(M, len(fields))
+			for k, v := range fields {
+				m[k] = v
+			}
+			fields, copied = m, true
+		}
+		fields[name] = _Serialized{serializer: f.serializer, value: value}
+	}
+	return fields
+}
+
+// _Deserializer scans a column into a serialized field.
+//
+// The field is reset to its zero value before being unmarshalled, and is
+// left as zero for NULL. If indirect, ptr points to a nil pointer to the
+// field, which is allocated only for non-NULL, see addrsOf.
+type _Deserializer struct {
+	serializer Serializer
+	ptr        reflect.Value
+	indirect   bool
+}
+
+func (d _Deserializer) Scan(src interface{}) error {
+	v := d.ptr.Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	var data []byte
+	switch typed := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = typed
+	case string:
+		data = []byte(typed)
+	default:
+		return fmt.Errorf("taorm: cannot unmarshal %T", src)
+	}
+
+	if d.indirect {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	return d.serializer.Unmarshal(data, v.Addr().Interface())
+}
@@ -0,0 +1,162 @@
+package taorm
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Prefs struct {
+	Theme string
+	Size  int
+}
+
+type Setting struct {
+	ID    int64
+	Tags  []string               `taorm:"serializer:json"`
+	Meta  map[string]interface{} `taorm:"serializer:json"`
+	Prefs *Prefs                 `taorm:"serializer:json"`
+	Blob  Prefs                  `taorm:"serializer:gob"`
+	Names []string               `taorm:"serializer:csv"`
+}
+
+func (Setting) TableName() string {
+	return `settings`
+}
+
+type _CSVSerializer struct{}
+
+func (_CSVSerializer) Marshal(v interface{}) (driver.Value, error) {
+	return strings.Join(v.([]string), ","), nil
+}
+
+func (_CSVSerializer) Unmarshal(data []byte, v interface{}) error {
+	*v.(*[]string) = strings.Split(string(data), ",")
+	return nil
+}
+
+func TestSerializer(t *testing.T) {
+	RegisterSerializer(`csv`, _CSVSerializer{})
+
+	db := newTestDB(t)
+
+	logger := &_TestLogger{}
+	tdb := NewDB(db).WithLogger(logger)
+
+	setting := Setting{
+		Tags:  []string{`a`, `b`},
+		Prefs: &Prefs{Theme: `dark`},
+		Names: []string{`x`, `y`},
+	}
+	assert.Equal(t,
+		`INSERT INTO settings (tags,meta,prefs,blob,names) VALUES `+
+			`('["a","b"]',NULL,'{"Theme":"dark","Size":0}',`+gobOf(t, Prefs{})+`,'x,y')`,
+		tdb.Model(setting).CreateSQL(),
+	)
+
+	assert.NoError(t, tdb.Model(&setting).Create())
+	args := logger.logs[0].Args
+	assert.Len(t, args, 5)
+	value, err := args[0].(driver.Valuer).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `["a","b"]`, value)
+	value, err = args[1].(driver.Valuer).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	blob, err := _GobSerializer{}.Marshal(Prefs{Theme: `light`, Size: 2})
+	assert.NoError(t, err)
+	mimic.SetRows([]string{"id", "tags", "meta", "prefs", "blob", "names"}, [][]driver.Value{
+		{int64(1), []byte(`["a"]`), []byte(`{"k":1}`), `{"Theme":"dark"}`, blob, `x,y`},
+		{int64(2), nil, []byte(`{"j":2}`), nil, nil, nil},
+	})
+	var settings []Setting
+	assert.NoError(t, tdb.From(Setting{}).Find(&settings))
+	assert.Equal(t, []Setting{
+		{
+			ID:    1,
+			Tags:  []string{`a`},
+			Meta:  map[string]interface{}{`k`: float64(1)},
+			Prefs: &Prefs{Theme: `dark`},
+			Blob:  Prefs{Theme: `light`, Size: 2},
+			Names: []string{`x`, `y`},
+		},
+		{
+			ID:   2,
+			Meta: map[string]interface{}{`j`: float64(2)},
+		},
+	}, settings)
+
+	mimic.SetRows([]string{"id", "s__id", "s__tags"}, [][]driver.Value{
+		{int64(1), int64(1), []byte(`["a"]`)},
+		{int64(2), nil, nil},
+	})
+	type SettingRow struct {
+		ID      int64
+		Setting *Setting `taorm:"nested:s"`
+	}
+	var rows []SettingRow
+	assert.NoError(t, tdb.From(Setting{}).WithScanMode(ScanLenient).Find(&rows))
+	assert.Equal(t, []string{`a`}, rows[0].Setting.Tags)
+	assert.Nil(t, rows[1].Setting)
+
+	mimic.SetRows([]string{"id", "tags"}, [][]driver.Value{{int64(1), []byte(`{`)}})
+	assert.Error(t, tdb.From(Setting{}).Find(&settings))
+}
+
+func TestSerializerUpdates(t *testing.T) {
+	db := newTestDB(t)
+
+	logger := &_TestLogger{}
+	tdb := NewDBWithDialect(db, SQLite).WithLogger(logger)
+
+	assert.Equal(t,
+		`UPDATE settings SET meta=NULL,names=names,tags='["a","b"]' WHERE (id=1)`,
+		tdb.From(Setting{}).Where(`id=?`, 1).UpdateMapSQL(M{
+			`tags`:  []string{`a`, `b`},
+			`meta`:  nil,
+			`names`: Expr(`names`),
+		}),
+	)
+
+	_, err := tdb.From(Setting{}).Where(`id=?`, 1).UpdateMap(M{`tags`: []string{`a`}})
+	assert.NoError(t, err)
+	value, err := logger.logs[0].Args[0].(driver.Valuer).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `["a"]`, value)
+
+	assert.Equal(t,
+		`INSERT INTO settings (id,tags,meta,prefs,blob,names) VALUES (1,NULL,NULL,NULL,`+gobOf(t, Prefs{})+`,NULL) `+
+			`ON CONFLICT (id) DO UPDATE SET tags='["b"]'`,
+		tdb.Model(&Setting{ID: 1}).UpsertSQL(nil, M{`tags`: []string{`b`}}),
+	)
+
+	value, err = _Serialized{serializer: _JSONSerializer{}}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestSerializerTags(t *testing.T) {
+	type Unknown struct {
+		Tags []string `taorm:"serializer:unknown"`
+	}
+	_, err := getRegistered(Unknown{})
+	assert.Error(t, err)
+
+	type Unexported struct {
+		tags []string `taorm:"serializer:json"`
+	}
+	_, err = getRegistered(Unexported{})
+	assert.Error(t, err)
+}
+
+func gobOf(t *testing.T, v interface{}) string {
+	value, err := _GobSerializer{}.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return _StrArg{a: value}.String()
+}
@@ -0,0 +1,74 @@
+package taorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+var nullTimeType = reflect.TypeOf(sql.NullTime{})
+
+// addSoftDelete records the soft delete column of field.
+//
+// Rows of structs with a column tagged with `softDelete` are not deleted by
+// Delete, but marked as deleted by setting the column to the current time.
+// Rows marked as deleted are excluded by Find, Count and updates unless
+// the statement is Unscoped.
+func (s *_StructInfo) addSoftDelete(field reflect.StructField, fi _FieldInfo) error {
+	if _, ok := getTagOption(field, "softDelete"); !ok {
+		return nil
+	}
+	if s.softDelete != nil {
+		return fmt.Errorf("taorm: more than one soft delete columns: %s, %s", s.softDelete.name, fi.name)
+	}
+	if fi._type != nullTimeType && fi._type != timePtrType {
+		return fmt.Errorf("taorm: soft delete column %s must be sql.NullTime or *time.Time", fi.name)
+	}
+	s.softDelete = &fi
+	return nil
+}
+
+// notDeleted returns the condition that excludes rows marked as deleted
+// in table, which is prefixed to the column if not empty.
+func (s *_StructInfo) notDeleted(d Dialect, table string) (string, bool) {
+	if s == nil || s.softDelete == nil {
+		return "", false
+	}
+	column := quoteIdent(d, s.softDelete.name)
+	if table != "" {
+		column = table + "." + column
+	}
+	return column + " IS NULL", true
+}
+
+// Unscoped makes the statement see rows marked as deleted by soft delete,
+// and makes Delete delete rows permanently.
+func (s *Stmt) Unscoped() *Stmt {
+	s.unscoped = true
+	return s
+}
+
+// notDeleted returns the condition that excludes rows of the main table
+// marked as deleted, which is qualified by the table if there are more tables.
+func (s *Stmt) notDeleted() (string, bool) {
+	if s.unscoped {
+		return "", false
+	}
+	table := ""
+	if len(s.tableNames) > 1 || len(s.joinTables) > 0 {
+		table = s.table()
+	}
+	return s.structInfo().notDeleted(s.db.dialect, table)
+}
+
+// softDeleted returns the soft delete column of the main table,
+// or nil if rows should be deleted permanently.
+func (s *Stmt) softDeleted() *_FieldInfo {
+	if s.unscoped {
+		return nil
+	}
+	if info := s.structInfo(); info != nil {
+		return info.softDelete
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package taorm
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Comment struct {
+	ID        int64
+	PostID    int64
+	Content   string
+	DeletedAt sql.NullTime `taorm:"softDelete"`
+}
+
+func (Comment) TableName() string {
+	return `comments`
+}
+
+func TestSoftDelete(t *testing.T) {
+	db := newTestDB(t)
+
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tdb := NewDB(db)
+
+	assertSQLs(t, []_SQLTest{
+		{
+			`SELECT * FROM comments WHERE (post_id=1) AND (deleted_at IS NULL)`,
+			tdb.From(Comment{}).Where(`post_id=?`, 1).FindSQL(),
+		},
+		{
+			`SELECT * FROM comments WHERE (deleted_at IS NULL)`,
+			tdb.Model(Comment{}).FindSQL(),
+		},
+		{
+			`SELECT COUNT(1) FROM comments WHERE (deleted_at IS NULL)`,
+			tdb.From(Comment{}).CountSQL(),
+		},
+		{
+			`SELECT * FROM comments`,
+			tdb.From(Comment{}).Unscoped().FindSQL(),
+		},
+		{
+			`SELECT comments.* FROM comments INNER JOIN posts ON comments.post_id=posts.id WHERE (posts.id=1) AND (comments.deleted_at IS NULL)`,
+			tdb.From(Comment{}).InnerJoin(Post{}, `comments.post_id=posts.id`).Where(`posts.id=?`, 1).FindSQL(),
+		},
+		{
+			`SELECT posts.* FROM posts LEFT JOIN comments ON (comments.post_id=posts.id) AND comments.deleted_at IS NULL`,
+			tdb.From(Post{}).LeftJoin(Comment{}, `comments.post_id=posts.id`).FindSQL(),
+		},
+		{
+			`SELECT posts.* FROM posts LEFT JOIN comments ON (comments.post_id=posts.id OR comments.post_id=0) AND comments.deleted_at IS NULL`,
+			tdb.From(Post{}).LeftJoin(Comment{}, `comments.post_id=posts.id OR comments.post_id=0`).FindSQL(),
+		},
+		{
+			`UPDATE comments SET content='a' WHERE (id=1) AND (deleted_at IS NULL)`,
+			tdb.From(Comment{}).Where(`id=?`, 1).UpdateMapSQL(M{`content`: `a`}),
+		},
+		{
+			`UPDATE comments SET deleted_at=2023-11-14 22:13:20 +0000 UTC WHERE (id=1) AND (deleted_at IS NULL)`,
+			tdb.Model(&Comment{ID: 1}).DeleteSQL(),
+		},
+		{
+			`DELETE FROM comments WHERE (id=1)`,
+			tdb.Model(&Comment{ID: 1}).Unscoped().DeleteSQL(),
+		},
+	})
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	assert.Equal(t, ErrNoWhere, tdb.From(Comment{}).Delete().(*Error).Raw)
+	_, err := tdb.From(Comment{}).UpdateMap(M{`content`: `a`})
+	assert.Equal(t, ErrNoWhere, err.(*Error).Raw)
+	assert.Empty(t, *queries)
+
+	assert.NoError(t, tdb.From(Comment{}).DeleteAnyway())
+	assert.Equal(t, []string{`UPDATE comments SET deleted_at=? WHERE (deleted_at IS NULL)`}, *queries)
+
+	type BadDelete struct {
+		DeletedAt time.Time `taorm:"softDelete"`
+	}
+	_, err = getRegistered(BadDelete{})
+	assert.Error(t, err)
+}
@@ -2,10 +2,12 @@ package taorm
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -15,40 +17,51 @@ type _Where struct {
 	args  []interface{}
 }
 
+// build expands slice args to `?,?,?`.
+// `?`s are found by the same rules as rebind, those in quoted strings or
+// identifiers, or escaped as `??`, are not args.
 func (w _Where) build() (query string, args []interface{}) {
 	sb := bytes.NewBuffer(nil)
 	sb.Grow(len(query)) // should we reserve capacity for slice too?
 	var i int
-	for _, c := range w.query {
-		switch c {
-		case '?':
-			if i >= len(w.args) {
-				panic(fmt.Errorf("err where args count"))
-			}
-			value := reflect.ValueOf(w.args[i])
-			if value.Kind() == reflect.Slice {
-				n := value.Len()
-				marks := createSQLInMarks(n)
-				// sliceValueKind := value.Type().Elem().Kind()
-				// switch sliceValueKind {
-				// case reflect.String:
-				// 	marks = "(" + marks + ")"
-				// default:
-				// 	break
-				// }
-				sb.WriteString(marks)
-				for j := 0; j < n; j++ {
-					args = append(args, value.Index(j).Interface())
-				}
-			} else {
-				sb.WriteByte('?')
-				args = append(args, w.args[i])
+	rest := w.query
+	for {
+		k, escaped := nextMark(rest)
+		if k == -1 {
+			break
+		}
+		sb.WriteString(rest[:k])
+		if escaped {
+			sb.WriteString("??")
+			rest = rest[k+2:]
+			continue
+		}
+		rest = rest[k+1:]
+		if i >= len(w.args) {
+			panic(fmt.Errorf("err where args count"))
+		}
+		value := reflect.ValueOf(w.args[i])
+		if value.Kind() == reflect.Slice {
+			n := value.Len()
+			marks := createSQLInMarks(n)
+			// sliceValueKind := value.Type().Elem().Kind()
+			// switch sliceValueKind {
+			// case reflect.String:
+			// 	marks = "(" + marks + ")"
+			// default:
+			// 	break
+			// }
+			sb.WriteString(marks)
+			for j := 0; j < n; j++ {
+				args = append(args, value.Index(j).Interface())
 			}
-			i++
-		default:
-			sb.WriteRune(c)
+		} else {
+			sb.WriteByte('?')
+			args = append(args, w.args[i])
 		}
+		i++
 	}
+	sb.WriteString(rest)
 	if i != len(w.args) {
 		panic(fmt.Errorf("err where args count"))
 	}
@@ -85,7 +98,12 @@ type Stmt struct {
 	tableNames []string
 	joinTables []_Join
 	fields     []string
-	ands       []_Where
+	returning  []string
+	conflict   *_Conflict
+	ands       []_Cond
+	omits      []string
+	preloads   []string
+	unscoped   bool
 	groupBy    string
 	having     string
 	orderBy    string
@@ -113,6 +131,7 @@ type _Join struct {
 	by    string
 	table string
 	where _Where
+	info  *_StructInfo // nil if table is a string
 }
 
 func (s *Stmt) InnerJoin(table any, on string, args ...any) *Stmt {
@@ -129,6 +148,7 @@ func (s *Stmt) RightJoin(table any, on string, args ...any) *Stmt {
 
 func (s *Stmt) join(by string, table any, on string, args ...any) *Stmt {
 	name := ""
+	var info *_StructInfo
 	switch typed := table.(type) {
 	case string:
 		name = typed
@@ -138,6 +158,7 @@ func (s *Stmt) join(by string, table any, on string, args ...any) *Stmt {
 			panic(WrapError(err))
 		}
 		name = n
+		info, _ = getRegistered(typed)
 	}
 
 	s.joinTables = append(s.joinTables, _Join{
@@ -147,12 +168,15 @@ func (s *Stmt) join(by string, table any, on string, args ...any) *Stmt {
 			query: on,
 			args:  args,
 		},
+		info: info,
 	})
 
 	return s
 }
 
 // Select ...
+//
+// For UpdateModel, only the selected columns are updated.
 func (s *Stmt) Select(fields string) *Stmt {
 	if len(fields) > 0 {
 		s.fields = append(s.fields, fields)
@@ -160,13 +184,35 @@ func (s *Stmt) Select(fields string) *Stmt {
 	return s
 }
 
-// Where ...
+// Omit excludes the comma-separated columns from UpdateModel.
+func (s *Stmt) Omit(fields string) *Stmt {
+	if len(fields) > 0 {
+		s.omits = append(s.omits, fields)
+	}
+	return s
+}
+
+// Returning sets the columns that are read back into the model by Create.
+//
+// It is used to get server-generated values like primary keys and defaults.
+// columns can be "*" to read back all columns.
+// The dialect must support INSERT ... RETURNING.
+func (s *Stmt) Returning(columns ...string) *Stmt {
+	s.returning = append(s.returning, columns...)
+	return s
+}
+
+// Where adds a condition that is ANDed with existing conditions.
+// Use `??` for a literal `?` that is not a bind variable, see DB.Exec.
 func (s *Stmt) Where(query string, args ...interface{}) *Stmt {
-	w := _Where{
-		query: query,
-		args:  args,
+	return s.WhereCond(Cond(query, args...))
+}
+
+// WhereCond is like Where but adds a condition created by Cond, And, Or or Not.
+func (s *Stmt) WhereCond(c _Cond) *Stmt {
+	if !c.empty() {
+		s.ands = append(s.ands, c)
 	}
-	s.ands = append(s.ands, w)
 	return s
 }
 
@@ -178,6 +224,31 @@ func (s *Stmt) WhereIf(cond bool, query string, args ...interface{}) *Stmt {
 	return s
 }
 
+// Or adds a condition that is ORed with all existing conditions.
+//
+// e.g.: Where("a=1").Where("b=2").Or("c=3") makes `((a=1) AND (b=2)) OR (c=3)`.
+func (s *Stmt) Or(query string, args ...interface{}) *Stmt {
+	return s.OrCond(Cond(query, args...))
+}
+
+// OrCond is like Or but adds a condition created by Cond, And, Or or Not.
+func (s *Stmt) OrCond(c _Cond) *Stmt {
+	if c.empty() {
+		return s
+	}
+	if len(s.ands) == 0 {
+		s.ands = append(s.ands, c)
+		return s
+	}
+	s.ands = []_Cond{Or(And(s.ands...), c)}
+	return s
+}
+
+// Not adds a negated condition that is ANDed with existing conditions.
+func (s *Stmt) Not(query string, args ...interface{}) *Stmt {
+	return s.WhereCond(Not(Cond(query, args...)))
+}
+
 // GroupBy ...
 func (s *Stmt) GroupBy(groupBy string) *Stmt {
 	s.groupBy = groupBy
@@ -209,26 +280,79 @@ func (s *Stmt) Offset(offset int64) *Stmt {
 	return s
 }
 
+// table returns the main table of the statement, or empty if unknown.
+func (s *Stmt) table() string {
+	if len(s.tableNames) > 0 {
+		return s.tableNames[0]
+	}
+	return ""
+}
+
+// structInfo returns the info of the model or the struct table,
+// or nil if neither is known.
+func (s *Stmt) structInfo() *_StructInfo {
+	if s.info != nil {
+		return s.info
+	}
+	if _, ok := s.fromTable.(string); s.fromTable == nil || ok {
+		return nil
+	}
+	info, err := getRegistered(s.fromTable)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
 // noWheres returns true if no SQL conditions.
-// Includes and, or.
+// Includes and, or, and the primary key of the model.
 func (s *Stmt) noWheres() bool {
-	return len(s.ands) <= 0
+	if len(s.ands) > 0 {
+		return false
+	}
+	_, ok := s.pkeyCond()
+	return !ok
 }
 
-func (s *Stmt) buildWheres() (string, []interface{}) {
-	if s.model != nil {
-		id, ok := s.info.getPrimaryKey(s.model)
-		s.WhereIf(ok, "id=?", id)
+// pkeyCond returns the condition of the primary key of the model if it is set.
+func (s *Stmt) pkeyCond() (_Cond, bool) {
+	if s.model == nil {
+		return _Cond{}, false
+	}
+	panicIf(isSliceModel(s.model), "slice models can only be created")
+	ids, ok := s.info.getPrimaryKey(s.model)
+	if !ok {
+		return _Cond{}, false
 	}
+	conds := make([]string, 0, len(ids))
+	for _, name := range s.info.pkeyNames() {
+		conds = append(conds, quoteIdent(s.db.dialect, name)+"=?")
+	}
+	return Cond(strings.Join(conds, " AND "), ids...), true
+}
 
-	if s.noWheres() {
+// buildWheres builds the WHERE clause of conditions, followed by extra
+// conditions and the primary key of the model.
+// s is left unchanged, so it can be built many times.
+func (s *Stmt) buildWheres(extra ..._Cond) (string, []interface{}) {
+	conds := append(s.ands[:len(s.ands):len(s.ands)], extra...)
+	if pkey, ok := s.pkeyCond(); ok {
+		conds = append(conds, pkey)
+	}
+
+	// not counted as a condition by noWheres.
+	if notDeleted, ok := s.notDeleted(); ok {
+		conds = append(conds, Cond(notDeleted))
+	}
+
+	if len(conds) == 0 {
 		return "", nil
 	}
 
 	var args []interface{}
 	sb := bytes.NewBuffer(nil)
 	sb.WriteString(" WHERE ")
-	for i, w := range s.ands {
+	for i, w := range conds {
 		if i > 0 {
 			sb.WriteString(" AND ")
 		}
@@ -251,8 +375,12 @@ func (s *Stmt) buildJoins() (string, []any) {
 		sb.WriteString(j.table)
 		sb.WriteString(" ON ")
 		query, xargs := j.where.build()
-		sb.WriteString(query)
 		args = append(args, xargs...)
+		if notDeleted, ok := j.info.notDeleted(s.db.dialect, j.table); ok && !s.unscoped {
+			// parenthesized in case of ORs in query.
+			query = "(" + query + ") AND " + notDeleted
+		}
+		sb.WriteString(query)
 	}
 	return sb.String(), args
 }
@@ -265,17 +393,61 @@ func (s *Stmt) buildCreate() (*_StructInfo, string, []interface{}, error) {
 		return info, "", nil, err
 	}
 	args := info.ifacesOf(s.model)
+	// parts of a composite key are never auto-generated and always inserted.
+	var query string
+	if pks, ok := info.getPrimaryKey(s.model); ok || len(pks) > 1 {
+		args = append(pks, args...)
+		query = info.insertStrOf(s.db.dialect, true)
+	} else {
+		query = info.insertStrOf(s.db.dialect, false)
+	}
 	if len(args) == 0 {
 		return info, "", nil, ErrNoFields
 	}
-	var query string
-	if pk, ok := info.getPrimaryKey(s.model); ok {
-		args = append([]any{pk}, args...)
-		query = info.insertIdStr
-	} else {
-		query = info.insertstr
+	query, conflictArgs, err := s.buildConflict(info, query)
+	if err != nil {
+		return info, "", nil, err
+	}
+	args = append(args, conflictArgs...)
+	returning, err := s.buildReturning(s.returningColumns(info))
+	if err != nil {
+		return info, "", nil, err
+	}
+	return info, query + returning, args, nil
+}
+
+// buildReturning builds the RETURNING clause for columns.
+func (s *Stmt) buildReturning(columns []string) (string, error) {
+	if len(columns) == 0 {
+		return "", nil
+	}
+	if !s.db.dialect.Returning() {
+		return "", ErrNotSupported
+	}
+	quoted := make([]string, 0, len(columns))
+	for _, c := range columns {
+		if c != "*" {
+			c = quoteIdent(s.db.dialect, c)
+		}
+		quoted = append(quoted, c)
 	}
-	return info, query, args, nil
+	return ` RETURNING ` + strings.Join(quoted, ","), nil
+}
+
+// returningColumns returns the columns to be read back after inserting.
+// The primary key is read back by RETURNING if the dialect doesn't
+// support LastInsertId.
+func (s *Stmt) returningColumns(info *_StructInfo) []string {
+	if len(s.returning) > 0 {
+		return append([]string(nil), s.returning...)
+	}
+	if s.db.dialect.LastInsertID() || !info.hasSinglePrimaryKey() {
+		return nil
+	}
+	if _, ok := info.getPrimaryKey(s.model); ok {
+		return nil
+	}
+	return info.pkeyNames()
 }
 
 func (s *Stmt) tryFindTableName(out interface{}) (string, error) {
@@ -286,7 +458,7 @@ func (s *Stmt) tryFindTableName(out interface{}) (string, error) {
 	if info.tableName == "" {
 		return "", fmt.Errorf("trying to use auto-registered struct table name")
 	}
-	return info.tableName, nil
+	return quoteIdent(s.db.dialect, info.tableName), nil
 }
 
 func (s *Stmt) buildSelect(out interface{}, isCount bool) (string, []interface{}, error) {
@@ -300,6 +472,7 @@ func (s *Stmt) buildSelect(out interface{}, isCount bool) (string, []interface{}
 			return "", nil, err
 		}
 		s.tableNames = append(s.tableNames, name)
+		s.fromTable = out
 	}
 
 	panicIf(len(s.tableNames) == 0, "model is empty")
@@ -310,7 +483,9 @@ func (s *Stmt) buildSelect(out interface{}, isCount bool) (string, []interface{}
 		strFields = "COUNT(1)"
 	} else {
 		fields := []string{}
-		if len(s.fields) == 0 {
+		if nested := s.nestedFields(out); nested != nil {
+			fields = nested
+		} else if len(s.fields) == 0 {
 			if len(s.joinTables) == 0 {
 				fields = []string{"*"}
 			} else {
@@ -365,6 +540,27 @@ func (s *Stmt) buildSelect(out interface{}, isCount bool) (string, []interface{}
 	return query, args, nil
 }
 
+// nestedFields returns the select list for out with nested structs,
+// or nil if out has no nested structs or fields are selected.
+func (s *Stmt) nestedFields(out interface{}) []string {
+	if len(s.fields) != 0 {
+		return nil
+	}
+	ty, err := structType(out)
+	if err != nil || !isModelType(ty) {
+		return nil
+	}
+	info, err := getRegistered(out)
+	if err != nil || len(info.nested) == 0 {
+		return nil
+	}
+	table := s.structInfo()
+	if table == nil {
+		return nil
+	}
+	return info.nestedSelect(s.db.dialect, table.tableName)
+}
+
 func (s *Stmt) buildUpdateMap(fields map[string]interface{}) (string, []interface{}, error) {
 	panicIf(len(s.tableNames) == 0, "model is empty")
 	panicIf(s.raw.query != "", "cannot use raw here")
@@ -374,40 +570,76 @@ func (s *Stmt) buildUpdateMap(fields map[string]interface{}) (string, []interfac
 		return "", nil, ErrNoFields
 	}
 
+	if info := s.structInfo(); info != nil {
+		fields = info.withSerialized(info.withUpdateTimes(fields, timeNow()))
+	}
+
+	sets, args := buildSets(s.db.dialect, fields)
+	query += sets
+
+	whereQuery, whereArgs := s.buildWheres()
+	query += whereQuery
+	args = append(args, whereArgs...)
+
+	query += s.buildLimit()
+
+	return query, args, nil
+}
+
+// buildSets builds `a=?,b=?` pairs of fields, sorted by names.
+// Values of type _Expr are used as raw SQL expressions.
+//
+// Names are quoted by d if they are reserved words, other names that are
+// not simple identifiers, e.g.: `t.a`, are used as is.
+func buildSets(d Dialect, fields map[string]interface{}) (string, []interface{}) {
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
 	updates := make([]string, 0, len(fields))
 	args := make([]interface{}, 0, len(fields))
 
-	for field, value := range fields {
-		switch tv := value.(type) {
+	for _, field := range names {
+		column := field
+		if reSimpleIdent.MatchString(field) {
+			column = quoteIdent(d, field)
+		}
+		switch tv := fields[field].(type) {
 		case _Expr:
 			eq, ea := _Where(tv).build()
-			pair := field + "=" + eq
+			pair := column + "=" + eq
 			updates = append(updates, pair)
 			args = append(args, ea...)
 		default:
-			pair := field + "=?"
+			pair := column + "=?"
 			updates = append(updates, pair)
-			args = append(args, value)
+			args = append(args, tv)
 		}
 	}
 
-	query += strings.Join(updates, ",")
-
-	whereQuery, whereArgs := s.buildWheres()
-	query += whereQuery
-	args = append(args, whereArgs...)
-
-	query += s.buildLimit()
-
-	return query, args, nil
+	return strings.Join(updates, ","), args
 }
 
 func (s *Stmt) buildUpdateModel(model interface{}) (string, []interface{}, error) {
 	panicIf(len(s.tableNames) == 0, "model is empty")
 	panicIf(s.raw.query != "", "cannot use raw here")
-	query := s.info.updatestr
-	args := s.info.ifacesOf(model)
-	whereQuery, whereArgs := s.buildWheres()
+	query := s.info.updateStrOf(s.db.dialect)
+	args := s.info.updateArgsOf(model, s.info.insertFields)
+	if len(s.fields) > 0 || len(s.omits) > 0 {
+		fields, err := s.info.updateFieldsOf(s.fields, s.omits)
+		if err != nil {
+			return "", nil, err
+		}
+		query = s.info.buildUpdateStrOf(s.db.dialect, fields)
+		args = s.info.updateArgsOf(model, fields)
+	}
+	var extra []_Cond
+	if s.info.version != nil {
+		extra = append(extra, Cond(quoteIdent(s.db.dialect, s.info.version.name)+"=?", s.info.versionOf(model)))
+	}
+	whereQuery, whereArgs := s.buildWheres(extra...)
 	query += whereQuery
 	args = append(args, whereArgs...)
 	return query, args, nil
@@ -416,6 +648,10 @@ func (s *Stmt) buildUpdateModel(model interface{}) (string, []interface{}, error
 func (s *Stmt) buildDelete() (string, []interface{}, error) {
 	panicIf(len(s.tableNames) == 0, "model is empty")
 	panicIf(s.raw.query != "", "cannot use raw here")
+	if column := s.softDeleted(); column != nil {
+		now := timeNow()
+		return s.buildUpdateMap(s.structInfo().withUpdateTimes(M{column.name: now}, now))
+	}
 	var args []interface{}
 	query := `DELETE FROM ` + strings.Join(s.tableNames, ",")
 
@@ -482,38 +718,103 @@ func (s *Stmt) buildOrderBy() (string, error) {
 	return orderBy, nil
 }
 
-func (s *Stmt) buildLimit() (limit string) {
-	if s.limit > 0 {
-		limit += ` LIMIT ` + fmt.Sprint(s.limit)
-		if s.offset >= 0 {
-			limit += ` OFFSET ` + fmt.Sprint(s.offset)
-		}
-	}
-	return
+func (s *Stmt) buildLimit() string {
+	return s.db.dialect.Limit(s.limit, s.offset)
 }
 
 // Create ...
 func (s *Stmt) Create() error {
+	return s.CreateContext(context.Background())
+}
+
+// CreateContext ...
+//
+// If the model is a slice, it is the same as CreateBatchContext(ctx, 0).
+func (s *Stmt) CreateContext(ctx context.Context) error {
+	if isSliceModel(s.model) {
+		return s.CreateBatchContext(ctx, 0)
+	}
+	return WrapError(s.create(ctx))
+}
+
+func (s *Stmt) create(ctx context.Context) error {
+	if err := s.info.callHook(_BeforeCreate, s.db, s.model); err != nil {
+		return err
+	}
+	s.info.setCreateTimes(s.model, timeNow())
+
 	info, query, args, err := s.buildCreate()
 	if err != nil {
-		return WrapError(err)
+		return err
 	}
 
-	dumpSQL(query, args...)
+	if len(s.returningColumns(info)) > 0 {
+		if err := s.scanReturning(ctx, info, []interface{}{s.model}, query, args); err != nil {
+			return err
+		}
+		return info.callHook(_AfterCreate, s.db, s.model)
+	}
 
-	result, err := s.db.Exec(query, args...)
+	result, err := s.db.exec(ctx, OpCreate, s.table(), query, args...)
 	if err != nil {
-		return WrapError(err)
+		return err
+	}
+
+	if s.db.dialect.LastInsertID() && info.hasIntPrimaryKey() {
+		// the id is meaningless if the row is ignored or updated on conflicts.
+		if s.conflict != nil {
+			if n, err := result.RowsAffected(); err != nil || n != 1 {
+				return info.callHook(_AfterCreate, s.db, s.model)
+			}
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		info.setPrimaryKey(s.model, id)
 	}
 
-	id, err := result.LastInsertId()
+	return info.callHook(_AfterCreate, s.db, s.model)
+}
+
+// scanReturning executes the INSERT ... RETURNING query and
+// scans the returned rows into models in order.
+//
+// sql.ErrNoRows is returned if there are fewer rows than models, unless
+// conflicting rows are ignored, which are not returned.
+func (s *Stmt) scanReturning(ctx context.Context, info *_StructInfo, models []interface{}, query string, args []interface{}) error {
+	rows, err := s.db.query(ctx, OpCreate, s.table(), query, args...)
 	if err != nil {
-		return WrapError(err)
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
 	}
 
-	info.setPrimaryKey(s.model, id)
+	layout, err := info.layoutOf(columns, s.db.scanMode)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	n := 0
+	for ; n < len(models) && rows.Next(); n++ {
+		if err := info.scanRow(rows, models[n], layout, info.addrsOf(models[n], layout)); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if n < len(models) && (s.conflict == nil || !s.conflict.ignore) {
+		return sql.ErrNoRows
+	}
+
+	return rows.Close()
 }
 
 // MustCreate ...
@@ -524,7 +825,18 @@ func (s *Stmt) MustCreate() {
 }
 
 // CreateSQL ...
+//
+// If the model is a slice, all elements are inserted in one statement.
 func (s *Stmt) CreateSQL() string {
+	if isSliceModel(s.model) {
+		elems := sliceElems(s.model)
+		query, args, _, err := s.buildCreateBatch(s.info, elems)
+		if err != nil {
+			panic(WrapError(err))
+		}
+		return strSQL(query, args...)
+	}
+
 	_, query, args, err := s.buildCreate()
 	if err != nil {
 		panic(WrapError(err))
@@ -534,13 +846,24 @@ func (s *Stmt) CreateSQL() string {
 
 // Find ...
 func (s *Stmt) Find(out interface{}) error {
+	return s.FindContext(context.Background(), out)
+}
+
+// FindContext ...
+func (s *Stmt) FindContext(ctx context.Context, out interface{}) error {
 	query, args, err := s.buildSelect(out, false)
 	if err != nil {
 		return WrapError(err)
 	}
 
-	dumpSQL(query, args...)
-	return ScanRows(out, s.db, query, args...)
+	rows, err := s.db.query(ctx, OpFind, s.table(), query, args...)
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := scanRows(s.db, out, rows); err != nil {
+		return err
+	}
+	return WrapError(s.preload(ctx, out))
 }
 
 // MustFind ...
@@ -558,24 +881,42 @@ func (s *Stmt) FindSQL() string {
 	return strSQL(query, args...)
 }
 
-// FindSQL ...
+// FindSQLRaw returns the query with bind variables of the dialect.
 func (s *Stmt) FindSQLRaw() string {
 	query, _, err := s.buildSelect(s.model, false)
 	if err != nil {
 		panic(WrapError(err))
 	}
-	return query
+	return rebind(s.db.dialect, query)
+}
+
+// Pluck finds values of column into out, which is a *[]primitive.
+func (s *Stmt) Pluck(column string, out interface{}) error {
+	return s.PluckContext(context.Background(), column, out)
+}
+
+// PluckContext ...
+func (s *Stmt) PluckContext(ctx context.Context, column string, out interface{}) error {
+	return s.Select(column).FindContext(ctx, out)
 }
 
 // Count ...
 func (s *Stmt) Count(out interface{}) error {
+	return s.CountContext(context.Background(), out)
+}
+
+// CountContext ...
+func (s *Stmt) CountContext(ctx context.Context, out interface{}) error {
 	query, args, err := s.buildSelect(s.fromTable, true)
 	if err != nil {
 		return WrapError(err)
 	}
 
-	dumpSQL(query, args...)
-	return ScanRows(out, s.db, query, args...)
+	rows, err := s.db.query(ctx, OpCount, s.table(), query, args...)
+	if err != nil {
+		return WrapError(err)
+	}
+	return scanRows(s.db, out, rows)
 }
 
 // MustCount ...
@@ -594,7 +935,7 @@ func (s *Stmt) CountSQL() string {
 	return strSQL(query, args...)
 }
 
-func (s *Stmt) updateMap(fields M, anyway bool) (sql.Result, error) {
+func (s *Stmt) updateMap(ctx context.Context, fields M, anyway bool) (sql.Result, error) {
 	if len(fields) == 0 {
 		return nil, ErrNoFields
 	}
@@ -608,9 +949,7 @@ func (s *Stmt) updateMap(fields M, anyway bool) (sql.Result, error) {
 		return nil, ErrNoWhere
 	}
 
-	dumpSQL(query, args...)
-
-	res, err := s.db.Exec(query, args...)
+	res, err := s.db.exec(ctx, OpUpdate, s.table(), query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -618,43 +957,79 @@ func (s *Stmt) updateMap(fields M, anyway bool) (sql.Result, error) {
 	return res, nil
 }
 
-func (s *Stmt) updateModel(model interface{}) (sql.Result, error) {
+func (s *Stmt) updateModel(ctx context.Context, model interface{}) (sql.Result, error) {
+	if err := s.info.callHook(_BeforeUpdate, s.db, model); err != nil {
+		return nil, err
+	}
+	s.info.setUpdateTimes(model, timeNow())
+
 	query, args, err := s.buildUpdateModel(model)
 	if err != nil {
 		return nil, err
 	}
 
-	dumpSQL(query, args...)
-
-	res, err := s.db.Exec(query, args...)
+	res, err := s.db.exec(ctx, OpUpdate, s.table(), query, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.info.version != nil {
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, &StaleObjectError{
+				Table:   s.info.tableName,
+				Version: s.info.versionOf(model),
+			}
+		}
+		s.info.incrVersion(model)
+	}
+
 	return res, nil
 }
 
 // UpdateMap ...
 func (s *Stmt) UpdateMap(updates M) (sql.Result, error) {
-	res, err := s.updateMap(updates, false)
+	return s.UpdateMapContext(context.Background(), updates)
+}
+
+// UpdateMapContext ...
+func (s *Stmt) UpdateMapContext(ctx context.Context, updates M) (sql.Result, error) {
+	res, err := s.updateMap(ctx, updates, false)
 	return res, WrapError(err)
 }
 
 // UpdateMapAnyway ...
 func (s *Stmt) UpdateMapAnyway(updates M) (sql.Result, error) {
-	res, err := s.updateMap(updates, true)
+	return s.UpdateMapAnywayContext(context.Background(), updates)
+}
+
+// UpdateMapAnywayContext ...
+func (s *Stmt) UpdateMapAnywayContext(ctx context.Context, updates M) (sql.Result, error) {
+	res, err := s.updateMap(ctx, updates, true)
 	return res, WrapError(err)
 }
 
-// UpdateModel ...
+// UpdateModel updates all columns of model.
+//
+// If model has a version column, the row is updated only if its version is
+// the same as model's, and the version of model is incremented; otherwise
+// a StaleObjectError is returned.
 func (s *Stmt) UpdateModel(model interface{}) (sql.Result, error) {
-	res, err := s.updateModel(model)
+	return s.UpdateModelContext(context.Background(), model)
+}
+
+// UpdateModelContext ...
+func (s *Stmt) UpdateModelContext(ctx context.Context, model interface{}) (sql.Result, error) {
+	res, err := s.updateModel(ctx, model)
 	return res, WrapError(err)
 }
 
 // MustUpdateMap ...
 func (s *Stmt) MustUpdateMap(updates M) sql.Result {
-	res, err := s.updateMap(updates, false)
+	res, err := s.updateMap(context.Background(), updates, false)
 	if err != nil {
 		panic(err)
 	}
@@ -663,7 +1038,7 @@ func (s *Stmt) MustUpdateMap(updates M) sql.Result {
 
 // MustUpdateMapAnyway ...
 func (s *Stmt) MustUpdateMapAnyway(updates M) sql.Result {
-	res, err := s.updateMap(updates, true)
+	res, err := s.updateMap(context.Background(), updates, true)
 	if err != nil {
 		panic(err)
 	}
@@ -672,7 +1047,7 @@ func (s *Stmt) MustUpdateMapAnyway(updates M) sql.Result {
 
 // MustUpdateModel ...
 func (s *Stmt) MustUpdateModel(model interface{}) sql.Result {
-	res, err := s.updateModel(model)
+	res, err := s.updateModel(context.Background(), model)
 	if err != nil {
 		panic(err)
 	}
@@ -697,7 +1072,13 @@ func (s *Stmt) UpdateModelSQL(model interface{}) string {
 	return strSQL(query, args...)
 }
 
-func (s *Stmt) _delete(anyway bool) error {
+func (s *Stmt) _delete(ctx context.Context, anyway bool) error {
+	if s.model != nil {
+		if err := s.info.callHook(_BeforeDelete, s.db, s.model); err != nil {
+			return err
+		}
+	}
+
 	query, args, err := s.buildDelete()
 	if err != nil {
 		return err
@@ -707,9 +1088,7 @@ func (s *Stmt) _delete(anyway bool) error {
 		return ErrNoWhere
 	}
 
-	dumpSQL(query, args...)
-
-	_, err = s.db.Exec(query, args...)
+	_, err = s.db.exec(ctx, OpDelete, s.table(), query, args...)
 	if err != nil {
 		return err
 	}
@@ -719,12 +1098,22 @@ func (s *Stmt) _delete(anyway bool) error {
 
 // Delete ...
 func (s *Stmt) Delete() error {
-	return WrapError(s._delete(false))
+	return s.DeleteContext(context.Background())
+}
+
+// DeleteContext ...
+func (s *Stmt) DeleteContext(ctx context.Context) error {
+	return WrapError(s._delete(ctx, false))
 }
 
 // DeleteAnyway ...
 func (s *Stmt) DeleteAnyway() error {
-	return WrapError(s._delete(true))
+	return s.DeleteAnywayContext(context.Background())
+}
+
+// DeleteAnywayContext ...
+func (s *Stmt) DeleteAnywayContext(ctx context.Context) error {
+	return WrapError(s._delete(ctx, true))
 }
 
 // MustDelete ...
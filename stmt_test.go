@@ -1,9 +1,12 @@
 package taorm
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/movsb/taorm/mimic"
@@ -20,15 +23,32 @@ func (User) TableName() string {
 }
 
 type Like struct {
-	ID     int64
-	UserID int64
-	LikeID int64
+	UserID int64 `taorm:"pk"`
+	LikeID int64 `taorm:"pk"`
 }
 
 func (Like) TableName() string {
 	return `likes`
 }
 
+type Account struct {
+	UserID int64 `taorm:"pk"`
+	Name   string
+}
+
+func (Account) TableName() string {
+	return `accounts`
+}
+
+type Currency struct {
+	Code string `taorm:"name:code,pk"`
+	Name string
+}
+
+func (Currency) TableName() string {
+	return `currencies`
+}
+
 func TestSQLs(t *testing.T) {
 	db, err := sql.Open("mysql", "taorm:taorm@/taorm")
 	if err != nil {
@@ -124,6 +144,90 @@ func TestSQLs(t *testing.T) {
 			`SELECT * FROM users WHERE (name IN ('tao','yang'))`,
 			tdb.From(User{}).Where(`name IN (?)`, []string{`tao`, `yang`}).FindSQL(),
 		},
+		{
+			`INSERT INTO accounts (name) VALUES ('tao')`,
+			tdb.Model(Account{Name: `tao`}).CreateSQL(),
+		},
+		{
+			`INSERT INTO accounts (user_id,name) VALUES (1,'tao')`,
+			tdb.Model(Account{UserID: 1, Name: `tao`}).CreateSQL(),
+		},
+		{
+			`UPDATE accounts SET name='yang' WHERE (user_id=1)`,
+			tdb.Model(Account{UserID: 1}).UpdateModelSQL(Account{Name: `yang`}),
+		},
+		{
+			`INSERT INTO currencies (code,name) VALUES ('CNY','Yuan')`,
+			tdb.Model(Currency{Code: `CNY`, Name: `Yuan`}).CreateSQL(),
+		},
+		{
+			`UPDATE currencies SET name='RMB' WHERE (code='CNY')`,
+			tdb.Model(Currency{Code: `CNY`}).UpdateMapSQL(M{`name`: `RMB`}),
+		},
+		{
+			`DELETE FROM currencies WHERE (code='CNY')`,
+			tdb.Model(Currency{Code: `CNY`}).DeleteSQL(),
+		},
+		{
+			`INSERT INTO likes (user_id,like_id) VALUES (1,2)`,
+			tdb.Model(Like{UserID: 1, LikeID: 2}).CreateSQL(),
+		},
+		{
+			`UPDATE likes SET like_id=3 WHERE (user_id=1 AND like_id=2)`,
+			tdb.Model(Like{UserID: 1, LikeID: 2}).UpdateMapSQL(M{`like_id`: 3}),
+		},
+		{
+			`DELETE FROM likes WHERE (user_id=1 AND like_id=2)`,
+			tdb.Model(Like{UserID: 1, LikeID: 2}).DeleteSQL(),
+		},
+		{
+			`DELETE FROM likes WHERE (like_id=2)`,
+			tdb.Model(Like{LikeID: 2}).Where(`like_id=?`, 2).DeleteSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE ((age=1) OR (name IN ('a','b')))`,
+			tdb.From(User{}).WhereCond(Or(Cond(`age=?`, 1), Cond(`name IN (?)`, []string{`a`, `b`}))).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE ((age=1) OR (age=2)) AND (name='a')`,
+			tdb.From(User{}).WhereCond(Or(Cond(`age=?`, 1), Cond(`age=?`, 2))).Where(`name=?`, `a`).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (((age=1) AND (name='a')) OR (age=2))`,
+			tdb.From(User{}).Where(`age=?`, 1).Where(`name=?`, `a`).Or(`age=?`, 2).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (age=2)`,
+			tdb.From(User{}).Or(`age=?`, 2).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (age=1) AND (NOT (name='a'))`,
+			tdb.From(User{}).Where(`age=?`, 1).Not(`name=?`, `a`).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (NOT ((age=1) OR ((name='a') AND (age>2))))`,
+			tdb.From(User{}).WhereCond(Not(Or(Cond(`age=?`, 1), And(Cond(`name=?`, `a`), Cond(`age>?`, 2))))).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (age=1)`,
+			tdb.From(User{}).WhereCond(Or(And(), Cond(`age=?`, 1))).WhereIf(false, `age=?`, 2).WhereCond(Or()).FindSQL(),
+		},
+		{
+			`DELETE FROM users WHERE ((age=1) OR (age=2)) AND (id=1)`,
+			tdb.Model(User{ID: 1}).Or(`age=?`, 1).Or(`age=?`, 2).DeleteSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (name='it\'s?' AND age=1)`,
+			tdb.From(User{}).Where(`name='it\'s?' AND age=?`, 1).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (name='it''s?' AND age=1)`,
+			tdb.From(User{}).Where(`name='it''s?' AND age=?`, 1).FindSQL(),
+		},
+		{
+			`SELECT * FROM users WHERE (name LIKE '%?' AND tags ? 'a' AND age=1)`,
+			tdb.From(User{}).Where(`name LIKE '%?' AND tags ?? 'a' AND age=?`, 1).FindSQL(),
+		},
 	}
 	for _, test := range tests {
 		if test.want != test.got {
@@ -132,6 +236,113 @@ func TestSQLs(t *testing.T) {
 	}
 }
 
+func TestContext(t *testing.T) {
+	mimic.SetRows([]string{"id", "name", "age"}, [][]driver.Value{
+		{int64(1), "tao", 100},
+	})
+
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	var users []*User
+	if err := tdb.From(User{}).FindContext(context.Background(), &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("want 1 user, got %d", len(users))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := tdb.From(User{}).FindContext(ctx, &users); err == nil {
+		t.Fatal("find: want error from canceled context")
+	}
+	if err := tdb.Model(&User{Name: "tao"}).CreateContext(ctx); err == nil {
+		t.Fatal("create: want error from canceled context")
+	}
+	if err := tdb.Model(&User{ID: 1}).DeleteContext(ctx); err == nil {
+		t.Fatal("delete: want error from canceled context")
+	}
+	if err := tdb.TxCallContext(ctx, func(tx *DB) error { return nil }); err == nil {
+		t.Fatal("tx: want error from canceled context")
+	}
+}
+
+type Token struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+}
+
+func (Token) TableName() string {
+	return `tokens`
+}
+
+func TestCreateReturning(t *testing.T) {
+	db := newTestDB(t)
+
+	pg := NewDBWithDialect(db, PostgreSQL)
+
+	if want, got := `INSERT INTO users (name,age) VALUES ('tao',18) RETURNING id`,
+		pg.Model(User{Name: `tao`, Age: 18}).CreateSQL(); want != got {
+		t.Fatalf("want: %s\n got: %s", want, got)
+	}
+	if want, got := `INSERT INTO users (id,name,age) VALUES (1,'tao',18)`,
+		pg.Model(User{ID: 1, Name: `tao`, Age: 18}).CreateSQL(); want != got {
+		t.Fatalf("want: %s\n got: %s", want, got)
+	}
+
+	mimic.SetRows([]string{"id"}, [][]driver.Value{{int64(28)}})
+	user := User{Name: `tao`}
+	if err := pg.Model(&user).Create(); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != 28 {
+		t.Fatalf("id not set: %d", user.ID)
+	}
+
+	mimic.SetRows([]string{"id"}, nil)
+	if err := pg.Model(&User{Name: `tao`}).Create(); !IsNotFoundError(err) {
+		t.Fatalf("want not found error, got: %v", err)
+	}
+
+	now := time.Now()
+	mimic.SetRows([]string{"id", "created_at"}, [][]driver.Value{{"a-uuid", now}})
+	token := Token{Name: `tao`}
+	if err := pg.Model(&token).Returning("id", "created_at").Create(); err != nil {
+		t.Fatal(err)
+	}
+	if token.ID != `a-uuid` || !token.CreatedAt.Equal(now) {
+		t.Fatalf("returning not scanned: %+v", token)
+	}
+
+	err := NewDB(db).Model(&token).Returning("created_at").Create()
+	if !errors.Is(err.(*Error).Raw, ErrNotSupported) {
+		t.Fatalf("want ErrNotSupported, got: %v", err)
+	}
+}
+
+func TestCreatePrimaryKey(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	account := Account{Name: `tao`}
+	if err := tdb.Model(&account).Create(); err != nil {
+		t.Fatal(err)
+	}
+	if account.UserID != 1 {
+		t.Fatalf("primary key not set: %d", account.UserID)
+	}
+
+	currency := Currency{Name: `Yuan`}
+	if err := tdb.Model(&currency).Create(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func BenchmarkInsert(b *testing.B) {
 	user := User{
 		Name: "tao",
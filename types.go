@@ -1,12 +1,15 @@
 package taorm
 
 import (
+	"context"
 	"database/sql"
 )
 
 type _SQLCommon interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 }
 
 // M is a string-interface map that is used for Update*.
@@ -15,9 +18,11 @@ type M map[string]interface{}
 // Finder wraps method for SELECT.
 type Finder interface {
 	Find(out interface{}) error
+	FindContext(ctx context.Context, out interface{}) error
 	MustFind(out interface{})
 	FindSQL() string
 	Count(out interface{}) error
+	CountContext(ctx context.Context, out interface{}) error
 	MustCount(out interface{})
 	CountSQL() string
 }
@@ -26,3 +31,33 @@ type Finder interface {
 type TableNamer interface {
 	TableName() string
 }
+
+// BeforeCreater is implemented by models that are called by Create before
+// being inserted. Returning an error aborts the insert.
+type BeforeCreater interface {
+	BeforeCreate(tx *DB) error
+}
+
+// AfterCreater is implemented by models that are called by Create after
+// being inserted and having the primary key set.
+type AfterCreater interface {
+	AfterCreate(tx *DB) error
+}
+
+// BeforeUpdater is implemented by models that are called by UpdateModel
+// before being updated. Returning an error aborts the update.
+type BeforeUpdater interface {
+	BeforeUpdate(tx *DB) error
+}
+
+// AfterFinder is implemented by models that are called for each row
+// scanned into them by Find or ScanRows.
+type AfterFinder interface {
+	AfterFind(tx *DB) error
+}
+
+// BeforeDeleter is implemented by models that are called by Delete before
+// being deleted. Returning an error aborts the delete.
+type BeforeDeleter interface {
+	BeforeDelete(tx *DB) error
+}
@@ -0,0 +1,124 @@
+package taorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// splitFields splits comma-separated lists of columns.
+func splitFields(lists []string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, list := range lists {
+		for _, field := range strings.Split(list, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields[field] = true
+			}
+		}
+	}
+	return fields
+}
+
+// updateFieldsOf returns the fields to update by UpdateModel.
+//
+// If selects is not empty, only the selected columns are updated. Columns in
+// omits are not updated. The version and autoUpdateTime columns are always
+// updated, even if they are omitted, to keep the version of the model in sync.
+func (s *_StructInfo) updateFieldsOf(selects []string, omits []string) ([]_FieldInfo, error) {
+	selected := splitFields(selects)
+	omitted := splitFields(omits)
+	for name := range selected {
+		if !s.isInsertName(name) {
+			return nil, fmt.Errorf("taorm: no column to update: %s", name)
+		}
+	}
+
+	always := make(map[string]bool)
+	if s.version != nil {
+		always[s.version.name] = true
+	}
+	for _, t := range s.autoTimes {
+		if t.update {
+			always[t.field.name] = true
+		}
+	}
+
+	fields := make([]_FieldInfo, 0, len(s.insertFields))
+	for _, f := range s.insertFields {
+		if always[f.name] {
+			fields = append(fields, f)
+			continue
+		}
+		if omitted[f.name] || len(selected) > 0 && !selected[f.name] {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, ErrNoFields
+	}
+	return fields, nil
+}
+
+func (s *_StructInfo) isInsertName(name string) bool {
+	for _, n := range s.insertNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// changedNames returns names of the columns that differ between a and b,
+// excluding the version column.
+func (s *_StructInfo) changedNames(a, b interface{}) []string {
+	av := s.valuesOf(a, s.insertFields)
+	bv := s.valuesOf(b, s.insertFields)
+	names := []string{}
+	for i, f := range s.insertFields {
+		if s.version != nil && f.name == s.version.name {
+			continue
+		}
+		if !reflect.DeepEqual(av[i], bv[i]) {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// UpdateChanged updates the columns of modified that differ from original,
+// which are both pointers to the model type.
+//
+// It is like Select(changed columns).UpdateModel(modified), and Select is
+// ignored. No statement is executed if nothing is changed.
+func (s *Stmt) UpdateChanged(original, modified interface{}) (sql.Result, error) {
+	return s.UpdateChangedContext(context.Background(), original, modified)
+}
+
+// UpdateChangedContext ...
+func (s *Stmt) UpdateChangedContext(ctx context.Context, original, modified interface{}) (sql.Result, error) {
+	res, err := s.updateChanged(ctx, original, modified)
+	return res, WrapError(err)
+}
+
+// MustUpdateChanged ...
+func (s *Stmt) MustUpdateChanged(original, modified interface{}) sql.Result {
+	res, err := s.updateChanged(context.Background(), original, modified)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func (s *Stmt) updateChanged(ctx context.Context, original, modified interface{}) (sql.Result, error) {
+	panicIf(reflect.TypeOf(original) != reflect.TypeOf(modified), "original and modified are of different types")
+	changed := s.info.changedNames(original, modified)
+	if len(changed) == 0 {
+		return driver.RowsAffected(0), nil
+	}
+	s.fields = []string{strings.Join(changed, ",")}
+	return s.updateModel(ctx, modified)
+}
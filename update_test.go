@@ -0,0 +1,63 @@
+package taorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartialUpdate(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	user := User{ID: 1, Name: `tao`, Age: 18}
+	wallet := Wallet{ID: 1, Balance: 100, Version: 3}
+
+	assertSQLs(t, []_SQLTest{
+		{
+			`UPDATE users SET name='tao' WHERE (id=1)`,
+			tdb.Model(&user).Select(`name`).UpdateModelSQL(&user),
+		},
+		{
+			`UPDATE users SET age=18 WHERE (id=1)`,
+			tdb.Model(&user).Omit(`name`).UpdateModelSQL(&user),
+		},
+		{
+			`UPDATE wallets SET version=version+1 WHERE (version=3) AND (id=1)`,
+			tdb.Model(&wallet).Omit(`balance`).UpdateModelSQL(&wallet),
+		},
+		{
+			`UPDATE wallets SET balance=100,version=version+1 WHERE (version=3) AND (id=1)`,
+			tdb.Model(&wallet).Omit(`version`).UpdateModelSQL(&wallet),
+		},
+	})
+
+	assert.Panics(t, func() { tdb.Model(&user).Select(`unknown`).UpdateModelSQL(&user) })
+	assert.Panics(t, func() { tdb.Model(&user).Omit(`name,age`).UpdateModelSQL(&user) })
+
+	now := time.Unix(1700000000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	logger := &_TestLogger{}
+	tdb = tdb.WithLogger(logger)
+
+	original := Article{ID: 1, Title: `a`}
+	modified := original
+	_, err := tdb.Model(&modified).UpdateChanged(&original, &modified)
+	assert.NoError(t, err)
+	assert.Empty(t, logger.logs)
+
+	modified.Title = `b`
+	_, err = tdb.Model(&modified).UpdateChanged(&original, &modified)
+	assert.NoError(t, err)
+	assert.Len(t, logger.logs, 1)
+	assert.Equal(t, `UPDATE articles SET title=?,updated_at=? WHERE (id=?)`, logger.logs[0].Query)
+	assert.Equal(t, []interface{}{`b`, now.UnixMilli(), int64(1)}, logger.logs[0].Args)
+
+	_, err = tdb.Model(&modified).Omit(`created_at,updated_at`).UpdateModel(&modified)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE articles SET title=?,updated_at=? WHERE (id=?)`, logger.logs[1].Query)
+}
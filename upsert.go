@@ -0,0 +1,81 @@
+package taorm
+
+import (
+	"context"
+)
+
+// _Conflict is how an INSERT handles conflicting rows.
+type _Conflict struct {
+	ignore  bool     // ignore conflicting rows
+	columns []string // conflict target for upserts
+	updates M        // updates for upserts
+}
+
+// InsertIgnore makes Create and CreateBatch ignore rows that conflict with
+// existing rows on primary or unique keys.
+func (s *Stmt) InsertIgnore() *Stmt {
+	s.conflict = &_Conflict{ignore: true}
+	return s
+}
+
+// Upsert inserts the model, or updates the existing row by updates if the
+// model conflicts with it on conflictColumns.
+//
+// conflictColumns defaults to the primary key if empty. It is ignored by MySQL,
+// which updates rows conflicting on any primary or unique key.
+// Values in updates can be Expr, e.g.: M{"count": Expr("counters.count+?", 1)}.
+// Columns in Expr must be qualified by the table name to refer to the existing
+// row, because they are ambiguous with the row to insert on PostgreSQL.
+//
+// The model can also be a slice to upsert in batches.
+func (s *Stmt) Upsert(conflictColumns []string, updates M) error {
+	return s.UpsertContext(context.Background(), conflictColumns, updates)
+}
+
+// UpsertContext ...
+func (s *Stmt) UpsertContext(ctx context.Context, conflictColumns []string, updates M) error {
+	return s.upsert(conflictColumns, updates).CreateContext(ctx)
+}
+
+// MustUpsert ...
+func (s *Stmt) MustUpsert(conflictColumns []string, updates M) {
+	if err := s.Upsert(conflictColumns, updates); err != nil {
+		panic(err)
+	}
+}
+
+// UpsertSQL ...
+func (s *Stmt) UpsertSQL(conflictColumns []string, updates M) string {
+	return s.upsert(conflictColumns, updates).CreateSQL()
+}
+
+// upsert returns a copy of s that upserts, s is left unchanged.
+// The autoUpdateTime columns are updated too, and values of serialized
+// columns are serialized.
+func (s *Stmt) upsert(conflictColumns []string, updates M) *Stmt {
+	u := *s
+	u.conflict = &_Conflict{
+		columns: conflictColumns,
+		updates: s.info.withSerialized(s.info.withUpdateTimes(updates, timeNow())),
+	}
+	return &u
+}
+
+// buildConflict applies the conflict clause to the INSERT query.
+func (s *Stmt) buildConflict(info *_StructInfo, query string) (string, []interface{}, error) {
+	if s.conflict == nil {
+		return query, nil, nil
+	}
+	if s.conflict.ignore {
+		return s.db.dialect.InsertIgnore(query), nil, nil
+	}
+	if len(s.conflict.updates) == 0 {
+		return "", nil, ErrNoFields
+	}
+	columns := s.conflict.columns
+	if len(columns) == 0 {
+		columns = info.pkeyNames()
+	}
+	sets, args := buildSets(s.db.dialect, s.conflict.updates)
+	return query + s.db.dialect.Upsert(columns, sets), args, nil
+}
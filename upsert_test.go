@@ -0,0 +1,93 @@
+package taorm
+
+import (
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Counter struct {
+	Name  string `taorm:"pk"`
+	Count int
+}
+
+func (Counter) TableName() string {
+	return `counters`
+}
+
+func TestUpsertSQLs(t *testing.T) {
+	db := newTestDB(t)
+
+	my := NewDB(db)
+	pg := NewDBWithDialect(db, PostgreSQL)
+	lite := NewDBWithDialect(db, SQLite)
+
+	counter := Counter{Name: `a`, Count: 1}
+	updates := M{`count`: Expr(`counters.count+?`, 1)}
+
+	assertSQLs(t, []_SQLTest{
+		{
+			`INSERT INTO counters (name,count) VALUES ('a',1) ON DUPLICATE KEY UPDATE count=counters.count+1`,
+			my.Model(counter).UpsertSQL(nil, updates),
+		},
+		{
+			`INSERT INTO counters (name,count) VALUES ('a',1) ON CONFLICT (name) DO UPDATE SET count=counters.count+1`,
+			pg.Model(counter).UpsertSQL(nil, updates),
+		},
+		{
+			`INSERT INTO users (name,age) VALUES ('tao',18) ON CONFLICT (name) DO UPDATE SET age=20 RETURNING id`,
+			pg.Model(User{Name: `tao`, Age: 18}).UpsertSQL([]string{`name`}, M{`age`: 20}),
+		},
+		{
+			`INSERT INTO counters (name,count) VALUES ('a',1),('b',1) ON CONFLICT (name) DO UPDATE SET count=counters.count+1`,
+			lite.Model([]Counter{{`a`, 1}, {`b`, 1}}).UpsertSQL(nil, updates),
+		},
+		{
+			`INSERT IGNORE INTO counters (name,count) VALUES ('a',1)`,
+			my.Model(counter).InsertIgnore().CreateSQL(),
+		},
+		{
+			`INSERT INTO counters (name,count) VALUES ('a',1) ON CONFLICT DO NOTHING`,
+			lite.Model(counter).InsertIgnore().CreateSQL(),
+		},
+		{
+			`INSERT INTO users (name,age) VALUES ('tao',18) ON CONFLICT DO NOTHING RETURNING id`,
+			pg.Model(User{Name: `tao`, Age: 18}).InsertIgnore().CreateSQL(),
+		},
+	})
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	assert.NoError(t, my.Model(&counter).Upsert(nil, updates))
+	assert.Equal(t, []string{
+		`INSERT INTO counters (name,count) VALUES (?,?) ON DUPLICATE KEY UPDATE count=counters.count+?`,
+	}, *queries)
+
+	stmt := my.Model(&counter)
+	stmt.UpsertSQL(nil, updates)
+	assert.Equal(t, `INSERT INTO counters (name,count) VALUES ('a',1)`, stmt.CreateSQL())
+
+	assert.Equal(t,
+		`INSERT INTO users (name,age) VALUES ('a',0),('b',0) ON CONFLICT DO NOTHING`,
+		pg.Model([]User{{Name: `a`}, {Name: `b`}}).InsertIgnore().CreateSQL(),
+	)
+}
+
+func TestUpsertPrimaryKey(t *testing.T) {
+	db := newTestDB(t)
+	tdb := NewDB(db)
+
+	user := User{Name: `tao`}
+	assert.NoError(t, tdb.Model(&user).InsertIgnore().Create())
+	assert.Equal(t, int64(0), user.ID)
+
+	mimic.SetRowsAffected(2)
+	assert.NoError(t, tdb.Model(&user).Upsert([]string{`name`}, M{`age`: 1}))
+	assert.Equal(t, int64(0), user.ID)
+
+	mimic.SetRowsAffected(1)
+	assert.NoError(t, tdb.Model(&user).Upsert([]string{`name`}, M{`age`: 1}))
+	assert.Equal(t, int64(1), user.ID)
+}
@@ -29,8 +29,16 @@ func isColumnField(field reflect.StructField) bool {
 	if !ast.IsExported(field.Name) {
 		return false
 	}
+	return isColumnType(field.Type)
+}
 
-	t := field.Type
+// isColumnType returns true if values of t can be scanned from a column.
+//
+// Pointers to column types are nullable columns, which are nil for NULL.
+func isColumnType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Kind() != reflect.Ptr && isColumnType(t.Elem())
+	}
 
 	switch t.Kind() {
 	case reflect.Bool, reflect.String,
@@ -40,7 +48,9 @@ func isColumnField(field reflect.StructField) bool {
 		return true
 	}
 
-	valueable := t.Implements(valuerType)
+	// Value can be implemented by either value or pointer receiver.
+	// For the latter, the address of the field is passed to the driver.
+	valueable := t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType)
 	scannable := reflect.PtrTo(t).Implements(scannerType) && !t.Implements(scannerType)
 	if valueable && scannable {
 		return true
@@ -75,6 +85,25 @@ func getColumnName(field reflect.StructField) string {
 	return toSnakeCase(field.Name)
 }
 
+// getTagOption gets the value of option name in the taorm tag of field.
+//
+// e.g.: for `taorm:"name:id,pk"`, option `name` is "id", option `pk` is ""
+// and both options are present.
+func getTagOption(field reflect.StructField, name string) (string, bool) {
+	tag := field.Tag.Get("taorm")
+	kvs := strings.Split(tag, ",")
+	for _, kv := range kvs {
+		s := strings.SplitN(kv, ":", 2)
+		if s[0] == name {
+			if len(s) > 1 {
+				return s[1], true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
+
 type _EmptyEface struct {
 	typ *struct{}
 	ptr unsafe.Pointer
@@ -95,15 +124,23 @@ func panicIf(cond bool, v interface{}) {
 	}
 }
 
-func dumpSQL(query string, args ...interface{}) {
-	// fmt.Println(strSQL(query, args...))
-}
-
 type _StrArg struct {
 	a any
 }
 
 func (v _StrArg) String() string {
+	rv := reflect.ValueOf(v.a)
+	if v.a == nil || rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return `NULL`
+	}
+	if valuer, ok := v.a.(driver.Valuer); ok {
+		if value, err := valuer.Value(); err == nil {
+			return _StrArg{a: value}.String()
+		}
+	}
+	if rv.Kind() == reflect.Ptr {
+		return _StrArg{a: rv.Elem().Interface()}.String()
+	}
 	switch typed := v.a.(type) {
 	case string:
 		return fmt.Sprintf(`'%s'`, strings.ReplaceAll(typed, `'`, `\'`))
@@ -117,10 +154,28 @@ func strSQL(query string, args ...interface{}) string {
 	for _, a := range args {
 		sa = append(sa, _StrArg{a: a})
 	}
-	for i := range args {
-		args[i] = sa[i]
+	sb := strings.Builder{}
+	for {
+		i, escaped := nextMark(query)
+		if i == -1 {
+			break
+		}
+		sb.WriteString(query[:i])
+		switch {
+		case escaped:
+			sb.WriteByte('?')
+			query = query[i+2:]
+			continue
+		case len(sa) > 0:
+			sb.WriteString(sa[0].String())
+			sa = sa[1:]
+		default:
+			sb.WriteByte('?')
+		}
+		query = query[i+1:]
 	}
-	return fmt.Sprintf(strings.ReplaceAll(query, "?", "%v"), args...)
+	sb.WriteString(query)
+	return sb.String()
 }
 
 func structName(ty reflect.Type) string {
@@ -35,7 +35,10 @@ type ColumnFieldStruct struct {
 	F          func()         "false"
 	If         interface{}    "false"
 	M          map[string]int "false"
-	P          *int           "false"
+	P          *int           "true"
+	PP         **int          "false"
+	PS         *string        "true"
+	PSlice     *[]int         "false"
 	Slice      []int          "false"
 	S          string         "true"
 	Struct     struct{}       "false"
@@ -49,8 +52,53 @@ type ColumnFieldStruct struct {
 	Time  time.Time "true"
 	Bytes []byte    "true"
 
+	PtrTime       *time.Time      "true"
+	PtrNullString *sql.NullString "true"
+	PtrStruct     *struct{}       "false"
+
+	Null _Null[int] "true"
+
 	TypeWithScannerAndValuer      _TypeWithScannerAndValuer      "true"
 	TypeWithValueScannerAndValuer _TypeWithValueScannerAndValuer "false"
+	TypeWithPtrScannerAndValuer   _TypeWithPtrScannerAndValuer   "true"
+}
+
+// _Null is like sql.Null[T].
+type _Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+func (n _Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.V, nil
+}
+
+func (n *_Null[T]) Scan(value interface{}) error {
+	if value == nil {
+		n.V, n.Valid = *new(T), false
+		return nil
+	}
+	n.Valid = true
+	return convertAssign(&n.V, value)
+}
+
+// convertAssign converts value of int64 or string to *dest.
+func convertAssign(dest interface{}, value interface{}) error {
+	reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(value).Convert(reflect.TypeOf(dest).Elem()))
+	return nil
+}
+
+type _TypeWithPtrScannerAndValuer struct{}
+
+func (t *_TypeWithPtrScannerAndValuer) Value() (driver.Value, error) {
+	return "", nil
+}
+
+func (t *_TypeWithPtrScannerAndValuer) Scan(value interface{}) error {
+	return nil
 }
 
 type _TypeWithScannerAndValuer struct{}
@@ -141,3 +189,29 @@ func TestCreateSQLInMarks(t *testing.T) {
 		assert.Equal(t, s.m, createSQLInMarks(s.n))
 	}
 }
+
+func TestGetTagOption(t *testing.T) {
+	s := struct {
+		A int `taorm:"name:a,pk"`
+		B int `taorm:"pk:x"`
+		C int
+	}{}
+	r := reflect.TypeOf(s)
+	tests := []struct {
+		i     int
+		name  string
+		value string
+		ok    bool
+	}{
+		{0, `name`, `a`, true},
+		{0, `pk`, ``, true},
+		{1, `pk`, `x`, true},
+		{1, `name`, ``, false},
+		{2, `pk`, ``, false},
+	}
+	for _, test := range tests {
+		value, ok := getTagOption(r.Field(test.i), test.name)
+		assert.Equal(t, test.value, value)
+		assert.Equal(t, test.ok, ok)
+	}
+}
@@ -0,0 +1,62 @@
+package taorm
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// addVersion records the version column of field.
+//
+// A column tagged with `version` is used for optimistic locking by
+// UpdateModel, which updates the row only if the version is unchanged,
+// and increments the version.
+func (s *_StructInfo) addVersion(field reflect.StructField, fi _FieldInfo) error {
+	if _, ok := getTagOption(field, "version"); !ok {
+		return nil
+	}
+	if s.version != nil {
+		return fmt.Errorf("taorm: more than one version columns: %s, %s", s.version.name, fi.name)
+	}
+	switch fi._type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return fmt.Errorf("taorm: version column %s must be an integer", fi.name)
+	}
+	s.version = &fi
+	return nil
+}
+
+// updateArgsOf returns args for the SET clause of UPDATE of fields of out,
+// which doesn't include the version column.
+func (s *_StructInfo) updateArgsOf(out interface{}, fields []_FieldInfo) []interface{} {
+	args := s.valuesOf(out, fields)
+	if s.version == nil {
+		return args
+	}
+	for i, f := range fields {
+		if f.name == s.version.name {
+			return append(args[:i], args[i+1:]...)
+		}
+	}
+	return args
+}
+
+// versionOf returns the version of out.
+func (s *_StructInfo) versionOf(out interface{}) int64 {
+	v := s.valueOf(out, *s.version)
+	if v.CanInt() {
+		return v.Int()
+	}
+	return int64(v.Uint())
+}
+
+// incrVersion increments the version of out.
+func (s *_StructInfo) incrVersion(out interface{}) {
+	v := s.valueOf(out, *s.version)
+	if v.CanInt() {
+		v.SetInt(v.Int() + 1)
+	} else {
+		v.SetUint(v.Uint() + 1)
+	}
+}
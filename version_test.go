@@ -0,0 +1,53 @@
+package taorm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/movsb/taorm/mimic"
+	"github.com/stretchr/testify/assert"
+)
+
+type Wallet struct {
+	ID      int64
+	Balance int64
+	Version int `taorm:"version"`
+}
+
+func (Wallet) TableName() string {
+	return `wallets`
+}
+
+func TestVersion(t *testing.T) {
+	db := newTestDB(t)
+
+	tdb := NewDB(db)
+
+	wallet := Wallet{ID: 1, Balance: 100, Version: 3}
+	stmt := tdb.Model(&wallet)
+	for i := 0; i < 2; i++ {
+		assert.Equal(t,
+			`UPDATE wallets SET balance=100,version=version+1 WHERE (version=3) AND (id=1)`,
+			stmt.UpdateModelSQL(&wallet),
+		)
+	}
+
+	queries, stop := recordQueries()
+	defer stop()
+
+	mimic.SetRowsAffected(1)
+	_, err := stmt.UpdateModel(&wallet)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, wallet.Version)
+	assert.Equal(t, []string{`UPDATE wallets SET balance=?,version=version+1 WHERE (version=?) AND (id=?)`}, *queries)
+
+	mimic.SetRowsAffected(0)
+	_, err = tdb.Model(&wallet).UpdateModel(&wallet)
+	assert.True(t, IsStaleObjectError(err))
+	assert.True(t, IsStaleObjectError(fmt.Errorf("update: %w", err)))
+	assert.True(t, IsStaleObjectError(errors.Join(err, errors.New(`rollback`))))
+	assert.False(t, IsStaleObjectError(errors.New(`other`)))
+	assert.Equal(t, &StaleObjectError{Table: `wallets`, Version: 4}, err.(*Error).Err)
+	assert.Equal(t, 4, wallet.Version)
+}